@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBufferSize 默认预生成 ID 缓冲容量
+const DefaultBufferSize = 1024
+
+// Buffered 预生成 ID 缓冲, 由后台协程持续填充
+// Next 直接从缓冲取出 ID, 不需要读取时钟, 适用于对延迟敏感的调用路径
+// 缓冲中的 ID 在填充时生成, 其时间值早于取出时间
+type Buffered struct {
+	hits   uint64 // 从缓冲取得 ID 次数
+	misses uint64 // 缓冲为空时直接生成 ID 次数
+
+	sf   *Snowflake
+	ids  chan ID
+	done chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+// BufferStats 缓冲统计信息
+type BufferStats struct {
+	Size   int    // 缓冲容量
+	Len    int    // 当前缓冲 ID 数量
+	Hits   uint64 // 从缓冲取得 ID 次数
+	Misses uint64 // 缓冲为空时直接生成 ID 次数
+}
+
+// NewBuffered 创建预生成 ID 缓冲, size 小于等于 0 时使用 DefaultBufferSize
+func NewBuffered(sf *Snowflake, size int) *Buffered {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	b := &Buffered{
+		sf:   sf,
+		ids:  make(chan ID, size),
+		done: make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.fill()
+	return b
+}
+
+// Next 取出一个 ID, 缓冲为空时直接使用生成器产生 ID
+// 所有 ID 均来自同一生成器, 保证唯一, 但缓冲为空时取得的 ID 可能大于之后从缓冲取出的 ID
+func (b *Buffered) Next() ID {
+	select {
+	case id := <-b.ids:
+		atomic.AddUint64(&b.hits, 1)
+		return id
+	default:
+		atomic.AddUint64(&b.misses, 1)
+		return b.sf.ID()
+	}
+}
+
+// Stats 返回缓冲统计信息
+func (b *Buffered) Stats() BufferStats {
+	return BufferStats{
+		Size:   cap(b.ids),
+		Len:    len(b.ids),
+		Hits:   atomic.LoadUint64(&b.hits),
+		Misses: atomic.LoadUint64(&b.misses),
+	}
+}
+
+// Close 停止后台填充并等待协程退出, 可重复调用
+// 关闭后 Next 仍可使用, 先取完缓冲中剩余 ID, 之后直接使用生成器
+func (b *Buffered) Close() error {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	b.wg.Wait()
+	return nil
+}
+
+// fill 后台填充缓冲
+func (b *Buffered) fill() {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.done:
+			return
+		default:
+		}
+		id := b.sf.ID()
+		select {
+		case b.ids <- id:
+		case <-b.done:
+			return
+		}
+	}
+}
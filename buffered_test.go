@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBuffered(t *testing.T) {
+	sf := MustNew(Node(1))
+	b := NewBuffered(sf, 256)
+	defer b.Close()
+
+	// 等待缓冲填满
+	deadline := time.Now().Add(time.Second)
+	for b.Stats().Len < 256 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if st := b.Stats(); st.Size != 256 || st.Len != 256 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[ID]struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				id := b.Next()
+				mu.Lock()
+				if _, ok := seen[id]; ok {
+					t.Errorf("duplicate id %d", id)
+				}
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	st := b.Stats()
+	if st.Hits+st.Misses != 80000 {
+		t.Fatalf("hits(%d) + misses(%d) != 80000", st.Hits, st.Misses)
+	}
+}
+
+func TestBufferedClose(t *testing.T) {
+	sf := MustNew(Node(1))
+	b := NewBuffered(sf, 0)
+	if b.Stats().Size != DefaultBufferSize {
+		t.Fatalf("expected default size %d, got %d", DefaultBufferSize, b.Stats().Size)
+	}
+	b.Close()
+	b.Close()
+
+	n := b.Stats().Len
+	x := b.Next()
+	if y := b.Next(); x == y {
+		t.Fatalf("x(%d) & y(%d) are the same", x, y)
+	}
+	if n > 2 && b.Stats().Len != n-2 {
+		t.Fatal("buffer refilled after Close")
+	}
+}
+
+func BenchmarkBuffered(b *testing.B) {
+	buf := NewBuffered(MustNew(Node(1)), 4096)
+	defer buf.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = buf.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrChecksum is returned by ParseChecked when the check character does not match
+var ErrChecksum = errors.New("invalid checksum")
+
+// Checked 返回附加校验字符的 Base32 编码 ID, 适用于人工录入及口头传递
+// 校验字符使用 Base32 编码表上的 Luhn mod N 算法计算,
+// 可检出所有单字符错误及绝大多数相邻字符交换
+func (f ID) Checked() string {
+	var b [maxBase32Len + 1]byte
+	return string(f.AppendChecked(b[:0]))
+}
+
+// AppendChecked 追加附加校验字符的 Base32 编码 ID
+func (f ID) AppendChecked(dst []byte) []byte {
+	n := len(dst)
+	dst = f.AppendBase32(dst)
+	return append(dst, encodeBase32Map[luhnCheck(dst[n:])])
+}
+
+// ParseChecked 转化附加校验字符的 Base32 编码字符串到 ID 类型, 不区分大小写
+// 校验失败时返回 ErrChecksum
+func ParseChecked(s string) (ID, error) {
+	if s == "" {
+		return -1, ErrEmpty
+	}
+	if len(s) < 2 || len(s) > maxBase32Len+1 {
+		return -1, ErrInvalidLength
+	}
+
+	// 从右向左, 校验字符权重为 1, 依次交替为 2, 1
+	sum, factor := 0, 1
+	for i := len(s) - 1; i >= 0; i-- {
+		d := decodeBase32Map[lower(s[i])]
+		if d == 0xFF {
+			return -1, ErrInvalidBase32
+		}
+		sum += luhnAddend(int(d) * factor)
+		factor = 3 - factor
+	}
+	if sum%32 != 0 {
+		return -1, ErrChecksum
+	}
+
+	body := s[:len(s)-1]
+	var id int64
+	for i := 0; i < len(body); i++ {
+		d := int64(decodeBase32Map[lower(body[i])])
+		if id > (math.MaxInt64-d)/32 {
+			return -1, ErrOverflow
+		}
+		id = id*32 + d
+	}
+	return ID(id), nil
+}
+
+// luhnCheck 计算 Base32 编码 b 的 Luhn mod 32 校验值
+func luhnCheck(b []byte) int {
+	sum, factor := 0, 2
+	for i := len(b) - 1; i >= 0; i-- {
+		sum += luhnAddend(int(decodeBase32Map[b[i]]) * factor)
+		factor = 3 - factor
+	}
+	return (32 - sum%32) % 32
+}
+
+// luhnAddend 将乘积按 32 进制各位求和
+func luhnAddend(v int) int {
+	return v/32 + v%32
+}
+
+// lower 转化 ASCII 大写字母为小写
+func lower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c | 0x20
+	}
+	return c
+}
@@ -0,0 +1,72 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChecked(t *testing.T) {
+	sf := MustNew(Node(1))
+	for _, id := range append(codecIDs, sf.ID()) {
+		s := id.Checked()
+		if !strings.HasPrefix(s, id.Base32()) || len(s) != len(id.Base32())+1 {
+			t.Fatalf("Checked(%d) = %q, expected Base32 %q with check character", id, s, id.Base32())
+		}
+		for _, in := range []string{s, strings.ToUpper(s)} {
+			pID, err := ParseChecked(in)
+			if err != nil {
+				t.Fatalf("ParseChecked(%q) error %s", in, err)
+			}
+			if pID != id {
+				t.Fatalf("pID %v != id %v", pID, id)
+			}
+		}
+	}
+
+	tt := []struct {
+		s        string
+		expected error
+	}{
+		{"", ErrEmpty},
+		{"y", ErrInvalidLength},
+		{"yy!", ErrInvalidBase32},
+		{strings.Repeat("y", 15), ErrInvalidLength},
+		{strings.Repeat("y", 14) + "b", ErrInvalidLength},
+	}
+	for _, tc := range tt {
+		if _, err := ParseChecked(tc.s); err != tc.expected {
+			t.Fatalf("ParseChecked(%q): expected %v, got %v", tc.s, tc.expected, err)
+		}
+	}
+}
+
+// 所有单字符错误均应被检出, 相邻字符交换允许少量 (不超过 5%) 漏检
+func TestCheckedDetectsTypos(t *testing.T) {
+	sf := MustNew(Node(1))
+	var transpositions, missed int
+	for n := 0; n < 100; n++ {
+		s := sf.ID().Checked()
+		for i := 0; i < len(s); i++ {
+			for j := 0; j < len(encodeBase32Map); j++ {
+				if encodeBase32Map[j] == s[i] {
+					continue
+				}
+				typo := s[:i] + string(encodeBase32Map[j]) + s[i+1:]
+				if _, err := ParseChecked(typo); err != ErrChecksum {
+					t.Fatalf("ParseChecked(%q) typo of %q: expected ErrChecksum, got %v", typo, s, err)
+				}
+			}
+			if i+1 < len(s) && s[i] != s[i+1] {
+				transpositions++
+				swapped := s[:i] + string(s[i+1]) + string(s[i]) + s[i+2:]
+				if _, err := ParseChecked(swapped); err != ErrChecksum {
+					missed++
+				}
+			}
+		}
+	}
+	// Luhn mod N 无法检出少数相邻字符交换
+	if missed*20 > transpositions {
+		t.Fatalf("missed %d of %d transpositions", missed, transpositions)
+	}
+}
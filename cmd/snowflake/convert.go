@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/teamlint/snowflake"
+	"github.com/teamlint/snowflake/internal/cli"
+)
+
+// runConvert 转化数据流中的 ID 到新的布局
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("convert", stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: snowflake convert [flags] [file]\n\nRewrite decimal IDs in a CSV or JSON Lines stream from one layout to another.\nReads standard input when no file is given.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	from := cli.AddLayoutFlags(fs, "from-")
+	to := cli.AddLayoutFlags(fs, "to-")
+	format := fs.String("format", "csv", "input format: csv or jsonl")
+	columns := fs.String("columns", "0", "comma separated CSV column indexes holding IDs")
+	header := fs.Bool("header", false, "CSV input has a header row, copied unchanged")
+	fields := fs.String("fields", "id", "comma separated JSON Lines top-level fields holding IDs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fromLayout, err := from.Layout()
+	if err != nil {
+		return fmt.Errorf("from layout: %w", err)
+	}
+	toLayout, err := to.Layout()
+	if err != nil {
+		return fmt.Errorf("to layout: %w", err)
+	}
+	convert := func(s string) (string, error) {
+		id, err := snowflake.ParseString(s)
+		if err != nil {
+			return "", err
+		}
+		cID, err := snowflake.Convert(id, fromLayout, toLayout)
+		if err != nil {
+			return "", fmt.Errorf("id %s: %w", s, err)
+		}
+		return cID.String(), nil
+	}
+
+	in, err := openInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	switch *format {
+	case "csv":
+		cols, err := parseColumns(*columns)
+		if err != nil {
+			return err
+		}
+		return convertCSV(in, stdout, cols, *header, convert)
+	case "jsonl":
+		return convertJSONL(in, stdout, strings.Split(*fields, ","), convert)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// parseColumns 转化逗号分隔的列序号
+func parseColumns(s string) ([]int, error) {
+	var cols []int
+	for _, f := range strings.Split(s, ",") {
+		c, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || c < 0 {
+			return nil, fmt.Errorf("invalid column %q", f)
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// convertCSV 转化 CSV 指定列的 ID
+func convertCSV(r io.Reader, w io.Writer, cols []int, header bool, convert func(string) (string, error)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cw := csv.NewWriter(w)
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !(header && line == 1) {
+			for _, c := range cols {
+				if c >= len(record) {
+					return fmt.Errorf("line %d: column %d not found", line, c)
+				}
+				if record[c], err = convert(record[c]); err != nil {
+					return fmt.Errorf("line %d: %w", line, err)
+				}
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// convertJSONL 转化 JSON Lines 每行对象指定字段的 ID, 保持字段顺序, 其他内容原样输出
+// ID 可为字符串或数字, 输出保持原有形式
+func convertJSONL(r io.Reader, w io.Writer, fields []string, convert func(string) (string, error)) error {
+	convertField := func(raw json.RawMessage) (json.RawMessage, error) {
+		if len(raw) > 0 && raw[0] == '"' {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, err
+			}
+			c, err := convert(s)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(c)
+		}
+		c, err := convert(string(raw))
+		return json.RawMessage(c), err
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	bw := bufio.NewWriter(w)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		obj, err := decodeObject(b)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		for i, f := range obj {
+			if !contains(fields, f.key) || string(f.value) == "null" {
+				continue
+			}
+			if obj[i].value, err = convertField(f.value); err != nil {
+				return fmt.Errorf("line %d: field %s: %w", line, f.key, err)
+			}
+		}
+
+		bw.WriteByte('{')
+		for i, f := range obj {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(f.raw)
+			bw.WriteByte(':')
+			bw.Write(f.value)
+		}
+		bw.WriteString("}\n")
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// field JSON 对象顶层字段, raw 为原始键字节, 保持原有转义
+type field struct {
+	key   string
+	raw   []byte
+	value json.RawMessage
+}
+
+// decodeObject 按顺序解码 JSON 对象的顶层字段, b 为去除首尾空白的一行
+func decodeObject(b []byte) ([]field, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("expected JSON object")
+	}
+	var fields []field
+	for dec.More() {
+		start := dec.InputOffset()
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		// 键之前可能有分隔符及空白
+		raw := bytes.TrimLeft(b[start:dec.InputOffset()], ", \t\r\n")
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field{key: t.(string), raw: raw, value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	// 每行只能有一个对象, 不能丢弃其后的内容
+	if dec.More() || dec.InputOffset() != int64(len(b)) {
+		return nil, fmt.Errorf("unexpected data after JSON object at offset %d", dec.InputOffset())
+	}
+	return fields, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/teamlint/snowflake/internal/cli"
+)
+
+// runDecode 解码 ID 的时间, 节点及序列值
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("decode", stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: snowflake decode [flags] [id ...]\n\nPrint the time, node and sequence of IDs.\nReads IDs from standard input, one per line, when none are given.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	lf := cli.AddLayoutFlags(fs, "")
+	format := fs.String("format", "auto", "input format name or tag, auto detects the format")
+	output := fs.String("output", "table", "output: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output %q", *output)
+	}
+	formats, err := inputFormats(*format)
+	if err != nil {
+		return err
+	}
+	layout, err := lf.Layout()
+	if err != nil {
+		return err
+	}
+
+	var tw *tabwriter.Writer
+	enc := json.NewEncoder(stdout)
+	if *output == "table" {
+		tw = tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tFORMAT\tTIME\tNODE\tSEQ")
+	}
+	err = eachArg(fs.Args(), stdin, func(s string) error {
+		d, err := layout.Decode(s, formats...)
+		if err != nil {
+			return fmt.Errorf("%q: %w", s, err)
+		}
+		if tw == nil {
+			return enc.Encode(d)
+		}
+		_, err = fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\n", d.ID, d.Format, d.Time.Format("2006-01-02T15:04:05.000Z07:00"), d.Node, d.Seq)
+		return err
+	})
+	if tw != nil {
+		if ferr := tw.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
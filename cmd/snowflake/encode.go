@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/teamlint/snowflake"
+)
+
+// runEncode 转化 ID 编码格式
+func runEncode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("encode", stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: snowflake encode [flags] [id ...]\n\nConvert IDs between formats.\nReads IDs from standard input, one per line, when none are given.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	from := fs.String("from", "auto", "input format name or tag, auto detects the format")
+	to := fs.String("to", "base58", "output format name or tag")
+	tagged := fs.Bool("tagged", false, "prefix IDs with the format tag, e.g. b58:")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	formats, err := inputFormats(*from)
+	if err != nil {
+		return err
+	}
+	f, err := snowflake.ParseFormat(*to)
+	if err != nil {
+		return fmt.Errorf("%q: %w", *to, err)
+	}
+
+	w := bufio.NewWriter(stdout)
+	err = eachArg(fs.Args(), stdin, func(s string) error {
+		id, _, err := snowflake.ParseAny(s, formats...)
+		if err != nil {
+			return fmt.Errorf("%q: %w", s, err)
+		}
+		if *tagged {
+			w.WriteString(id.Tagged(f))
+		} else {
+			w.WriteString(f.Encode(id))
+		}
+		return w.WriteByte('\n')
+	})
+	if ferr := w.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
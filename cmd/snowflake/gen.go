@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/teamlint/snowflake"
+	"github.com/teamlint/snowflake/internal/cli"
+)
+
+// runGen 生成 ID
+func runGen(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("gen", stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: snowflake gen [flags]\n\nGenerate IDs, one per line.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	n := fs.Int("n", 1, "number of IDs to generate")
+	node := fs.Int64("node", 0, "node number, 0 uses $"+snowflake.EnvNode+" or the private IP")
+	layout := cli.AddLayoutFlags(fs, "")
+	format := fs.String("format", "decimal", "output format name or tag, e.g. base58 or b58")
+	tagged := fs.Bool("tagged", false, "prefix IDs with the format tag, e.g. b58:")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+	if *n < 0 {
+		return fmt.Errorf("invalid count %d", *n)
+	}
+	f, err := snowflake.ParseFormat(*format)
+	if err != nil {
+		return fmt.Errorf("%q: %w", *format, err)
+	}
+	sf, err := layout.New(snowflake.Node(*node))
+	if err != nil {
+		return err
+	}
+
+	// 分批生成并写出, 单批不超过一毫秒序列容量, 避免一次分配 n 个 ID
+	w := bufio.NewWriter(stdout)
+	chunk := int(sf.MaxSeq() + 1)
+	for remaining := *n; remaining > 0; {
+		size := chunk
+		if remaining < size {
+			size = remaining
+		}
+		for _, id := range sf.Batch(size) {
+			if *tagged {
+				w.WriteString(id.Tagged(f))
+			} else {
+				w.WriteString(f.Encode(id))
+			}
+			w.WriteByte('\n')
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		remaining -= size
+	}
+	return w.Flush()
+}
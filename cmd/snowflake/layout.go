@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/teamlint/snowflake"
+	"github.com/teamlint/snowflake/internal/cli"
+)
+
+// runLayout 输出 ID 布局信息
+func runLayout(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("layout", stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, "Usage: snowflake layout [flags]\n\nPrint the ID layout banner.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	node := fs.Int64("node", 0, "node number, 0 uses $"+snowflake.EnvNode+" or the private IP")
+	layout := cli.AddLayoutFlags(fs, "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+	sf, err := layout.New(snowflake.Node(*node))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, sf.Banner())
+	return err
+}
@@ -0,0 +1,137 @@
+// Command snowflake Snowflake ID 命令行工具
+//
+// 用法:
+//
+//	snowflake <command> [flags] [args]
+//
+// 命令:
+//
+//	gen      生成 ID
+//	decode   解码 ID 的时间, 节点及序列值
+//	encode   转化 ID 编码格式
+//	layout   输出 ID 布局信息
+//	convert  转化 CSV 或 JSON Lines 数据流中的 ID 到新的布局
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/teamlint/snowflake"
+)
+
+const usage = `Usage: snowflake <command> [flags] [args]
+
+Commands:
+  gen      generate IDs
+  decode   print the time, node and sequence of IDs
+  encode   convert IDs between formats
+  layout   print the ID layout banner
+  convert  rewrite IDs in CSV or JSON Lines streams from one layout to another
+
+Run "snowflake <command> -h" for command flags.
+`
+
+// command 子命令
+type command struct {
+	name string
+	run  func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"gen", runGen},
+	{"decode", runDecode},
+	{"encode", runEncode},
+	{"layout", runLayout},
+	{"convert", runConvert},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 执行子命令, 返回进程退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(args[1:], stdin, stdout, stderr)
+		if err == flag.ErrHelp {
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "snowflake %s: %v\n", c.name, err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Fprintf(stderr, "snowflake: unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
+
+// newFlagSet 创建子命令参数集
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("snowflake "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// openInput 打开输入文件, 参数为空或 - 时使用标准输入
+func openInput(args []string, stdin io.Reader) (io.ReadCloser, error) {
+	switch {
+	case len(args) == 0 || args[0] == "-":
+		return io.NopCloser(stdin), nil
+	case len(args) == 1:
+		return os.Open(args[0])
+	default:
+		return nil, fmt.Errorf("too many arguments")
+	}
+}
+
+// eachArg 依次处理参数, 无参数时依次处理标准输入的非空行
+func eachArg(args []string, stdin io.Reader, fn func(s string) error) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	sc := bufio.NewScanner(stdin)
+	for sc.Scan() {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" {
+			continue
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// inputFormats 返回输入格式名称或前缀标签对应的格式, auto 时返回所有格式
+func inputFormats(name string) ([]snowflake.Format, error) {
+	if name == "auto" {
+		return snowflake.Formats(), nil
+	}
+	f, err := snowflake.ParseFormat(name)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", name, err)
+	}
+	return []snowflake.Format{f}, nil
+}
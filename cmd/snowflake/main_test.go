@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+func runCmd(t *testing.T, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRunUsage(t *testing.T) {
+	if _, _, code := runCmd(t, ""); code != 2 {
+		t.Fatalf("no args exit code = %d, want 2", code)
+	}
+	if _, stderr, code := runCmd(t, "", "unknown"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Fatalf("unknown command exit code = %d, stderr %q", code, stderr)
+	}
+	if stdout, _, code := runCmd(t, "", "help"); code != 0 || !strings.Contains(stdout, "convert") {
+		t.Fatalf("help exit code = %d, stdout %q", code, stdout)
+	}
+}
+
+var convertArgs = []string{"-to-start", "1577836800000", "-to-node-bits", "16", "-to-seq-bits", "6"}
+
+func convertLayouts(t *testing.T) (from, to snowflake.Layout) {
+	t.Helper()
+	from, err := snowflake.NewLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err = snowflake.NewLayout(snowflake.StartTime(1577836800000), snowflake.NodeBits(16), snowflake.SeqBits(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return from, to
+}
+
+func testConvertIDs(t *testing.T) (id1, id2 snowflake.ID, args []string) {
+	t.Helper()
+	from, _ := convertLayouts(t)
+	id1, _ = from.Compose(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 3, 5)
+	id2, _ = from.Compose(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), 4, 6)
+	return id1, id2, append([]string(nil), convertArgs...)
+}
+
+func convertedID(t *testing.T, id snowflake.ID) string {
+	t.Helper()
+	from, to := convertLayouts(t)
+	c, err := snowflake.Convert(id, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.String()
+}
+
+func TestConvertCSV(t *testing.T) {
+	id1, id2, args := testConvertIDs(t)
+	in := "id,name,parent\n" + id1.String() + ",a," + id2.String() + "\n" + id2.String() + ",b," + id1.String() + "\n"
+	args = append([]string{"convert", "-header", "-columns", "0,2"}, args...)
+	stdout, stderr, code := runCmd(t, in, args...)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	c1, c2 := convertedID(t, id1), convertedID(t, id2)
+	want := "id,name,parent\n" + c1 + ",a," + c2 + "\n" + c2 + ",b," + c1 + "\n"
+	if stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestConvertJSONL(t *testing.T) {
+	id1, id2, args := testConvertIDs(t)
+	in := `{"name":"a","id":` + id1.String() + `,"ref":"` + id2.String() + `","n":1.5}` + "\n\n" +
+		`{"id":null,"ref":"` + id1.String() + `"}` + "\n" +
+		`{ "<a&b>" : "<x>", "\u00e9":1 , "id" : "` + id2.String() + `"}` + "\n"
+	args = append([]string{"convert", "-format", "jsonl", "-fields", "id,ref"}, args...)
+	stdout, stderr, code := runCmd(t, in, args...)
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	c1, c2 := convertedID(t, id1), convertedID(t, id2)
+	want := `{"name":"a","id":` + c1 + `,"ref":"` + c2 + `","n":1.5}` + "\n" +
+		`{"id":null,"ref":"` + c1 + `"}` + "\n" +
+		`{"<a&b>":"<x>","\u00e9":1,"id":"` + c2 + `"}` + "\n"
+	if stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	from, _ := convertLayouts(t)
+	id, _ := from.Compose(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 3, 200)
+	tests := []struct {
+		name string
+		in   string
+		args []string
+		want string
+	}{
+		{"seq overflow", "1\n" + id.String() + "\n", []string{"-to-seq-bits", "6"}, "line 2"},
+		{"invalid id", "abc\n", nil, "line 1"},
+		{"missing column", "1\n", []string{"-columns", "1"}, "column 1 not found"},
+		{"invalid json", "[1]\n", []string{"-format", "jsonl"}, "line 1"},
+		{"two objects", `{"id":1}` + "\n" + `{"id":1} {"id":"999"}` + "\n", []string{"-format", "jsonl"}, "line 2: unexpected data"},
+		{"trailing garbage", `{"id":1}garbage` + "\n", []string{"-format", "jsonl"}, "line 1: unexpected data"},
+		{"unknown format", "", []string{"-format", "xml"}, "unknown format"},
+		{"invalid layout", "", []string{"-to-node-bits", "20", "-to-seq-bits", "10"}, "to layout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, code := runCmd(t, tt.in, append([]string{"convert"}, tt.args...)...)
+			if code != 1 || !strings.Contains(stderr, tt.want) {
+				t.Fatalf("exit code = %d, stderr %q, want %q", code, stderr, tt.want)
+			}
+		})
+	}
+}
+
+func TestGen(t *testing.T) {
+	stdout, stderr, code := runCmd(t, "", "gen", "-n", "3", "-node", "7", "-node-bits", "16", "-seq-bits", "6", "-format", "b58")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	lines := strings.Fields(stdout)
+	if len(lines) != 3 {
+		t.Fatalf("got %d IDs, want 3: %q", len(lines), stdout)
+	}
+	layout, _ := snowflake.NewLayout(snowflake.NodeBits(16), snowflake.SeqBits(6))
+	for _, line := range lines {
+		id, err := snowflake.ParseBase58String(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node := layout.Node(id); node != 7 {
+			t.Fatalf("node = %d, want 7", node)
+		}
+	}
+
+	// 超过一毫秒序列容量时分批生成, ID 仍递增
+	stdout, stderr, code = runCmd(t, "", "gen", "-n", "150", "-node", "7", "-node-bits", "16", "-seq-bits", "6")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	lines = strings.Fields(stdout)
+	if len(lines) != 150 {
+		t.Fatalf("got %d IDs, want 150", len(lines))
+	}
+	var last snowflake.ID
+	for _, line := range lines {
+		id, err := snowflake.ParseString(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+	}
+
+	stdout, _, _ = runCmd(t, "", "gen", "-node", "1", "-format", "hex", "-tagged")
+	if !strings.HasPrefix(stdout, "hex:") {
+		t.Fatalf("stdout = %q, want hex: prefix", stdout)
+	}
+	if _, stderr, code := runCmd(t, "", "gen", "-format", "xml"); code != 1 || !strings.Contains(stderr, "unknown format") {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	layout, _ := snowflake.NewLayout()
+	id, _ := layout.Compose(time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC), 3, 5)
+
+	stdout, stderr, code := runCmd(t, "", "decode", id.Tagged(snowflake.FormatBase62))
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	for _, want := range []string{"TIME", id.String(), "base62", "2024-01-02T03:04:05.006Z"} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("stdout %q does not contain %q", stdout, want)
+		}
+	}
+
+	stdout, stderr, code = runCmd(t, id.Base58()+"\n", "decode", "-output", "json")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	want := `{"id":"` + id.String() + `","format":"base58","time":"2024-01-02T03:04:05.006Z","node":3,"seq":5}` + "\n"
+	if stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+
+	if _, stderr, code := runCmd(t, "", "decode", "!!"); code != 1 || !strings.Contains(stderr, "no format matched") {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	id := snowflake.ID(1234567890123456789)
+	stdout, stderr, code := runCmd(t, "", "encode", "-to", "base36", id.String(), id.Tagged(snowflake.FormatBase2))
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	if want := id.Base36() + "\n" + id.Base36() + "\n"; stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+
+	stdout, _, _ = runCmd(t, id.Base64()+"\n", "encode", "-from", "base64", "-to", "decimal", "-tagged")
+	if want := "dec:" + id.String() + "\n"; stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+	if _, stderr, code := runCmd(t, "", "encode", "-from", "base2", "123"); code != 1 || !strings.Contains(stderr, "123") {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	stdout, stderr, code := runCmd(t, "", "layout", "-node", "9", "-node-bits", "16", "-seq-bits", "6")
+	if code != 0 {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	sf := snowflake.MustNew(snowflake.Node(9), snowflake.NodeBits(16), snowflake.SeqBits(6))
+	if want := sf.Banner() + "\n"; stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+}
@@ -0,0 +1,97 @@
+// Command snowflaked HTTP Snowflake ID 服务
+//
+// 用法:
+//
+//	snowflaked [flags]
+//
+// 接口见 httpid 包, 收到 SIGINT 或 SIGTERM 时等待处理中的请求完成后退出.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/teamlint/snowflake"
+	"github.com/teamlint/snowflake/httpid"
+	"github.com/teamlint/snowflake/internal/cli"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := run(ctx, os.Args[1:], os.Stderr, nil)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "snowflaked: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// run 启动 HTTP 服务直到 ctx 结束, 然后优雅关闭
+// ready 不为 nil 时, 开始监听后发送监听地址
+func run(ctx context.Context, args []string, stderr io.Writer, ready chan<- net.Addr) error {
+	fs := flag.NewFlagSet("snowflaked", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8080", "listen address")
+	node := fs.Int64("node", 0, "node number, 0 uses $"+snowflake.EnvNode+" or the private IP")
+	layout := cli.AddLayoutFlags(fs, "")
+	maxBatch := fs.Int("max-batch", httpid.DefaultMaxBatch, "maximum number of IDs per /ids request")
+	shutdownTimeout := fs.Duration("shutdown-timeout", 10*time.Second, "time to wait for in-flight requests on shutdown")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sf, err := layout.New(snowflake.Node(*node))
+	if err != nil {
+		return err
+	}
+	logger := log.New(stderr, "[snowflaked] ", log.LstdFlags)
+	srv := &http.Server{
+		Handler:           httpid.NewHandler(sf, httpid.MaxBatch(*maxBatch)),
+		ReadHeaderTimeout: 5 * time.Second,
+		ErrorLog:          logger,
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	logger.Printf("listening on %s, node %d", ln.Addr(), sf.Node())
+	if ready != nil {
+		ready <- ln.Addr()
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(ln)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Printf("shutting down")
+	sctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(sctx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
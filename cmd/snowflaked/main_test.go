@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stderr bytes.Buffer
+	ready := make(chan net.Addr, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, []string{"-addr", "127.0.0.1:0", "-node", "5"}, &stderr, ready)
+	}()
+
+	var addr net.Addr
+	select {
+	case addr = <-ready:
+	case err := <-done:
+		t.Fatalf("run: %v, stderr %q", err, stderr.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("server not ready")
+	}
+
+	resp, err := http.Get("http://" + addr.String() + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ok\n" {
+		t.Fatalf("healthz status = %d, body %q", resp.StatusCode, body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	if !strings.Contains(stderr.String(), "shutting down") {
+		t.Fatalf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunFlags(t *testing.T) {
+	var stderr bytes.Buffer
+	if err := run(context.Background(), []string{"-node-bits", "20", "-seq-bits", "10"}, &stderr, nil); err == nil {
+		t.Fatal("expected error for invalid layout")
+	}
+	if err := run(context.Background(), []string{"-addr", "bad address"}, &stderr, nil); err == nil {
+		t.Fatal("expected error for invalid address")
+	}
+	stderr.Reset()
+	if err := run(context.Background(), []string{"-h"}, &stderr, nil); err != flag.ErrHelp {
+		t.Fatalf("expected flag.ErrHelp for -h, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "-addr") {
+		t.Fatalf("usage not printed for -h: %q", stderr.String())
+	}
+}
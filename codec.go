@@ -0,0 +1,349 @@
+package snowflake
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+)
+
+// Base64URLLen Base64URL 编码长度
+const Base64URLLen = 11
+
+// base64URLStrict 严格模式 Base64URL 解码, 拒绝末尾字符中非零的填充位
+var base64URLStrict = base64.RawURLEncoding.Strict()
+
+// 可表示非负 ID 的最大编码长度
+const (
+	maxBase32Len = 13
+	maxBase58Len = 11
+	maxBase62Len = 11
+)
+
+// 可表示 int64 的最大编码长度, 含符号位
+const (
+	maxBase2Len   = 65
+	maxDecimalLen = 20
+	maxHexLen     = 17
+	maxBase36Len  = 14
+)
+
+//********************************************************************************
+// Append 编码, 追加到 dst 并返回扩展后的切片, dst 容量足够时不分配内存
+
+// AppendString 追加十进制字符串 ID
+func (f ID) AppendString(dst []byte) []byte {
+	return strconv.AppendInt(dst, int64(f), 10)
+}
+
+// AppendBase2 追加 Base2 编码 ID
+func (f ID) AppendBase2(dst []byte) []byte {
+	return strconv.AppendInt(dst, int64(f), 2)
+}
+
+// AppendBase32 追加 Base32 编码 ID
+// 负数 ID 按 uint64 补码编码, ParseBase32不接受该编码, 返回 ErrOverflow
+func (f ID) AppendBase32(dst []byte) []byte {
+	return appendRadix(dst, uint64(f), encodeBase32Map)
+}
+
+// AppendBase36 追加 Base36 编码 ID
+func (f ID) AppendBase36(dst []byte) []byte {
+	return strconv.AppendInt(dst, int64(f), 36)
+}
+
+// AppendBase58 追加 Base58 编码 ID
+// 负数 ID 按 uint64 补码编码, ParseBase58不接受该编码, 返回 ErrOverflow
+func (f ID) AppendBase58(dst []byte) []byte {
+	return appendRadix(dst, uint64(f), encodeBase58Map)
+}
+
+// AppendBase64 追加 Base64 编码 ID, 与 Base64 相同编码十进制字符串
+func (f ID) AppendBase64(dst []byte) []byte {
+	var b [20]byte
+	src := f.AppendString(b[:0])
+	n := base64.StdEncoding.EncodedLen(len(src))
+	dst = grow(dst, n)
+	base64.StdEncoding.Encode(dst[len(dst)-n:], src)
+	return dst
+}
+
+// AppendBase64URL 追加 8 字节 Big Endian 编码的 URL 安全 Base64 编码 ID
+func (f ID) AppendBase64URL(dst []byte) []byte {
+	b := f.IntBytes()
+	dst = grow(dst, Base64URLLen)
+	base64.RawURLEncoding.Encode(dst[len(dst)-Base64URLLen:], b[:])
+	return dst
+}
+
+// AppendHex 追加小写十六进制编码 ID
+func (f ID) AppendHex(dst []byte) []byte {
+	return strconv.AppendInt(dst, int64(f), 16)
+}
+
+// AppendBase62 追加 Base62 编码 ID
+// 负数 ID 按 uint64 补码编码, ParseBase62不接受该编码, 返回 ErrOverflow
+func (f ID) AppendBase62(dst []byte) []byte {
+	return appendRadix(dst, uint64(f), encodeBase62Map)
+}
+
+// AppendBase32Crockford 追加 Crockford Base32 编码 ID, 使用大写字母
+// 负数 ID 按 uint64 补码编码, ParseBase32Crockford不接受该编码, 返回 ErrOverflow
+func (f ID) AppendBase32Crockford(dst []byte) []byte {
+	return appendRadix(dst, uint64(f), encodeCrockfordMap)
+}
+
+// appendRadix 使用编码表 alphabet 追加 v 的编码
+func appendRadix(dst []byte, v uint64, alphabet string) []byte {
+	var b [64]byte
+	base := uint64(len(alphabet))
+	i := len(b)
+	for v >= base {
+		i--
+		b[i] = alphabet[v%base]
+		v /= base
+	}
+	i--
+	b[i] = alphabet[v]
+	return append(dst, b[i:]...)
+}
+
+// grow 扩展 dst 长度 n 字节
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) < n {
+		b := make([]byte, len(dst), 2*cap(dst)+n)
+		copy(b, dst)
+		dst = b
+	}
+	return dst[:len(dst)+n]
+}
+
+//********************************************************************************
+// 字符串及字节数组解码, 成功时不分配内存
+
+// ParseBase2Bytes 转化 Base2 编码字节数组到 ID 类型
+func ParseBase2Bytes(b []byte) (ID, error) {
+	return parseID(string(b), 2)
+}
+
+// ParseBase32String 转化 Base32 编码字符串到 ID 类型
+func ParseBase32String(s string) (ID, error) {
+	return parseRadix(s, &decodeBase32Map, 32, maxBase32Len, ErrInvalidBase32)
+}
+
+// ParseBase36Bytes 转化 Base36 编码字节数组到 ID 类型
+func ParseBase36Bytes(b []byte) (ID, error) {
+	return parseID(string(b), 36)
+}
+
+// ParseBase58String 转化 Base58 编码字符串到 ID 类型
+func ParseBase58String(s string) (ID, error) {
+	return parseRadix(s, &decodeBase58Map, 58, maxBase58Len, ErrInvalidBase58)
+}
+
+// ParseBase64Bytes 转化 Base64 编码字节数组到 ID 类型
+func ParseBase64Bytes(b []byte) (ID, error) {
+	if len(b) == 0 {
+		return -1, ErrEmpty
+	}
+	var buf [21]byte
+	if base64.StdEncoding.DecodedLen(len(b)) > len(buf) {
+		return -1, ErrInvalidLength
+	}
+	n, err := base64.StdEncoding.Decode(buf[:], b)
+	if err != nil {
+		return -1, err
+	}
+	return ParseBytes(buf[:n])
+}
+
+// ParseBase64URL 转化 Base64URL 编码字符串到 ID 类型
+func ParseBase64URL(s string) (ID, error) {
+	return ParseBase64URLBytes([]byte(s))
+}
+
+// ParseBase64URLBytes 转化 Base64URL 编码字节数组到 ID 类型, 仅接受 AppendBase64URL 产生的规范编码
+func ParseBase64URLBytes(b []byte) (ID, error) {
+	if len(b) == 0 {
+		return -1, ErrEmpty
+	}
+	if len(b) != Base64URLLen {
+		return -1, ErrInvalidLength
+	}
+	// 严格模式保证每个 ID 只有一种编码
+	var buf [8]byte
+	n, err := base64URLStrict.Decode(buf[:], b)
+	if err != nil {
+		return -1, err
+	}
+	if n != len(buf) {
+		return -1, ErrInvalidLength
+	}
+	return ParseIntBytes(buf), nil
+}
+
+// ParseHex 转化十六进制编码字符串到 ID 类型, 不区分大小写
+func ParseHex(s string) (ID, error) {
+	return parseID(s, 16)
+}
+
+// ParseHexBytes 转化十六进制编码字节数组到 ID 类型, 不区分大小写
+func ParseHexBytes(b []byte) (ID, error) {
+	return parseID(string(b), 16)
+}
+
+// ParseBase62 转化 Base62 编码字符串到 ID 类型
+func ParseBase62(s string) (ID, error) {
+	return parseRadix(s, &decodeBase62Map, 62, maxBase62Len, ErrInvalidBase62)
+}
+
+// ParseBase62Bytes 转化 Base62 编码字节数组到 ID 类型
+func ParseBase62Bytes(b []byte) (ID, error) {
+	return ParseBase62(string(b))
+}
+
+// ParseBase32Crockford 转化 Crockford Base32 编码字符串到 ID 类型
+// 不区分大小写, I 和 L 视为 1, O 视为 0, 忽略连字符 -
+func ParseBase32Crockford(s string) (ID, error) {
+	if s == "" {
+		return -1, ErrEmpty
+	}
+	var id int64
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '-' {
+			continue
+		}
+		d := decodeCrockfordMap[s[i]]
+		if d == 0xFF {
+			return -1, ErrInvalidCrockford
+		}
+		if n++; n > maxBase32Len {
+			return -1, ErrInvalidLength
+		}
+		if id > (math.MaxInt64-int64(d))/32 {
+			return -1, ErrOverflow
+		}
+		id = id*32 + int64(d)
+	}
+	if n == 0 {
+		return -1, ErrEmpty
+	}
+	return ID(id), nil
+}
+
+// ParseBase32CrockfordBytes 转化 Crockford Base32 编码字节数组到 ID 类型
+func ParseBase32CrockfordBytes(b []byte) (ID, error) {
+	return ParseBase32Crockford(string(b))
+}
+
+// parseRadix 使用解码表 table 转化 base 进制编码字符串, 最长 maxLen 个字符
+func parseRadix(s string, table *[256]byte, base int64, maxLen int, invalid error) (ID, error) {
+	if s == "" {
+		return -1, ErrEmpty
+	}
+	var id int64
+	for i := 0; i < len(s); i++ {
+		d := table[s[i]]
+		if d == 0xFF {
+			return -1, invalid
+		}
+		if i >= maxLen {
+			return -1, ErrInvalidLength
+		}
+		if id > (math.MaxInt64-int64(d))/base {
+			return -1, ErrOverflow
+		}
+		id = id*base + int64(d)
+	}
+	return ID(id), nil
+}
+
+// parseID 检查 s 长度后按 base 进制转化, 超出 int64 最大编码长度时返回 ErrInvalidLength
+func parseID(s string, base int) (ID, error) {
+	maxLen := maxDecimalLen
+	switch base {
+	case 2:
+		maxLen = maxBase2Len
+	case 16:
+		maxLen = maxHexLen
+	case 36:
+		maxLen = maxBase36Len
+	}
+	if len(s) > maxLen {
+		return -1, ErrInvalidLength
+	}
+	i, err := parseInt(s, base)
+	return ID(i), err
+}
+
+// parseInt 与 strconv.ParseInt(s, base, 64) 相同, 但 s 不会逃逸, 字节数组转化时无需分配内存
+// 错误为 *strconv.NumError, 空字符串及溢出时其 Err 分别匹配 ErrEmpty 及 ErrOverflow,
+// 同时与 strconv.ParseInt 一样匹配 strconv.ErrSyntax 及 strconv.ErrRange
+func parseInt(s string, base int) (int64, error) {
+	const cutoff = 1 << 63
+	if s == "" {
+		return 0, numError(s, errEmptySyntax)
+	}
+	neg := s[0] == '-'
+	digits := s
+	if s[0] == '+' || s[0] == '-' {
+		digits = s[1:]
+		if digits == "" {
+			return 0, numError(s, strconv.ErrSyntax)
+		}
+	}
+
+	var n uint64
+	for i := 0; i < len(digits); i++ {
+		var d byte
+		switch c := digits[i]; {
+		case '0' <= c && c <= '9':
+			d = c - '0'
+		case 'a' <= c|0x20 && c|0x20 <= 'z':
+			d = c | 0x20 - 'a' + 10
+		default:
+			return 0, numError(s, strconv.ErrSyntax)
+		}
+		if int(d) >= base {
+			return 0, numError(s, strconv.ErrSyntax)
+		}
+		if n > (cutoff-uint64(d))/uint64(base) {
+			if neg {
+				return -cutoff, numError(s, errOverflowRange)
+			}
+			return cutoff - 1, numError(s, errOverflowRange)
+		}
+		n = n*uint64(base) + uint64(d)
+	}
+
+	if !neg && n >= cutoff {
+		return cutoff - 1, numError(s, errOverflowRange)
+	}
+	if neg {
+		return -int64(n), nil
+	}
+	return int64(n), nil
+}
+
+var (
+	errEmptySyntax   = &compatError{ErrEmpty, strconv.ErrSyntax}
+	errOverflowRange = &compatError{ErrOverflow, strconv.ErrRange}
+)
+
+// compatError 包装 err, 同时匹配 strconv.ParseInt 原有错误 compat, 错误信息使用 compat
+// 保持使用 errors.Is(err, strconv.ErrRange) 等判断的调用方兼容
+type compatError struct {
+	err    error
+	compat error
+}
+
+func (e *compatError) Error() string { return e.compat.Error() }
+
+func (e *compatError) Unwrap() error { return e.err }
+
+func (e *compatError) Is(target error) bool { return target == e.compat }
+
+// numError 返回 strconv.NumError, 复制 s 避免其逃逸
+func numError(s string, err error) error {
+	return &strconv.NumError{Func: "ParseInt", Num: string(append([]byte(nil), s...)), Err: err}
+}
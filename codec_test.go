@@ -0,0 +1,570 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var codecIDs = []ID{0, 1, 31, 32, 57, 58, 13587, 1116766490855473152, math.MaxInt64}
+
+func TestAppend(t *testing.T) {
+	sf := MustNew(Node(1))
+	ids := append(codecIDs, sf.ID())
+	prefix := []byte("id=")
+	for _, id := range ids {
+		tt := []struct {
+			name     string
+			appended []byte
+			expected string
+		}{
+			{"String", id.AppendString(prefix), id.String()},
+			{"Base2", id.AppendBase2(prefix), id.Base2()},
+			{"Base32", id.AppendBase32(prefix), id.Base32()},
+			{"Base36", id.AppendBase36(prefix), id.Base36()},
+			{"Base58", id.AppendBase58(prefix), id.Base58()},
+			{"Base64", id.AppendBase64(prefix), id.Base64()},
+			{"Base64URL", id.AppendBase64URL(prefix), id.Base64URL()},
+			{"Hex", id.AppendHex(prefix), strconv.FormatInt(int64(id), 16)},
+		}
+		for _, tc := range tt {
+			if string(tc.appended) != "id="+tc.expected {
+				t.Fatalf("Append%s(%d) = %q, expected %q", tc.name, id, tc.appended, "id="+tc.expected)
+			}
+		}
+	}
+}
+
+func TestParsePairs(t *testing.T) {
+	for _, id := range codecIDs {
+		tt := []struct {
+			name  string
+			parse func() (ID, error)
+		}{
+			{"Bytes", func() (ID, error) { return ParseBytes(id.AppendString(nil)) }},
+			{"Base2Bytes", func() (ID, error) { return ParseBase2Bytes(id.AppendBase2(nil)) }},
+			{"Base32String", func() (ID, error) { return ParseBase32String(id.Base32()) }},
+			{"Base36Bytes", func() (ID, error) { return ParseBase36Bytes(id.AppendBase36(nil)) }},
+			{"Base58String", func() (ID, error) { return ParseBase58String(id.Base58()) }},
+			{"Base64Bytes", func() (ID, error) { return ParseBase64Bytes(id.AppendBase64(nil)) }},
+			{"Hex", func() (ID, error) { return ParseHex(string(id.AppendHex(nil))) }},
+			{"HexBytes", func() (ID, error) { return ParseHexBytes(id.AppendHex(nil)) }},
+		}
+		for _, tc := range tt {
+			pID, err := tc.parse()
+			if err != nil {
+				t.Fatalf("Parse%s(%d) error %s", tc.name, id, err)
+			}
+			if pID != id {
+				t.Fatalf("Parse%s: pID %v != id %v", tc.name, pID, id)
+			}
+		}
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	tt := []struct {
+		s    string
+		base int
+	}{
+		{"", 10}, {"-", 10}, {"+1", 10}, {"-1", 10}, {"0", 10}, {"1a", 10},
+		{"9223372036854775807", 10}, {"9223372036854775808", 10},
+		{"-9223372036854775808", 10}, {"-9223372036854775809", 10},
+		{"1112316766490855473152", 10}, {"7FFFFFFFFFFFFFFF", 16}, {"8000000000000000", 16},
+		{"1z", 36}, {"1Z", 36}, {"102", 2}, {"zzzzzzzzzzzzz", 36},
+	}
+	for _, tc := range tt {
+		expected, expectedErr := strconv.ParseInt(tc.s, tc.base, 64)
+		i, err := parseInt(tc.s, tc.base)
+		if i != expected || (err == nil) != (expectedErr == nil) {
+			t.Fatalf("parseInt(%q, %d) = %d, %v, expected %d, %v", tc.s, tc.base, i, err, expected, expectedErr)
+		}
+		if err == nil {
+			continue
+		}
+		switch {
+		case tc.s == "":
+			expectedErr = ErrEmpty
+		case errors.Is(expectedErr, strconv.ErrRange):
+			expectedErr = ErrOverflow
+		default:
+			expectedErr = strconv.ErrSyntax
+		}
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("parseInt(%q, %d) error %q, expected %q", tc.s, tc.base, err, expectedErr)
+		}
+	}
+}
+
+// codecs 所有编码及解码函数对, signed 为 true 时支持负数 ID
+// signed 为 false 的编码按 uint64 补码编码负数 ID, 解码时返回 ErrOverflow, 见 TestUnsignedNegative
+var codecs = []struct {
+	name   string
+	signed bool
+	encode func(ID) string
+	parse  func(string) (ID, error)
+}{
+	{"String", true, ID.String, ParseString},
+	{"Bytes", true, ID.String, func(s string) (ID, error) { return ParseBytes([]byte(s)) }},
+	{"Base2", true, ID.Base2, ParseBase2},
+	{"Base2Bytes", true, ID.Base2, func(s string) (ID, error) { return ParseBase2Bytes([]byte(s)) }},
+	{"Base32", false, ID.Base32, func(s string) (ID, error) { return ParseBase32([]byte(s)) }},
+	{"Base32String", false, ID.Base32, ParseBase32String},
+	{"Base32Crockford", false, ID.Base32Crockford, ParseBase32Crockford},
+	{"Base36", true, ID.Base36, ParseBase36},
+	{"Base36Bytes", true, ID.Base36, func(s string) (ID, error) { return ParseBase36Bytes([]byte(s)) }},
+	{"Base58", false, ID.Base58, func(s string) (ID, error) { return ParseBase58([]byte(s)) }},
+	{"Base58String", false, ID.Base58, ParseBase58String},
+	{"Base62", false, ID.Base62, ParseBase62},
+	{"Base64", true, ID.Base64, ParseBase64},
+	{"Base64URL", true, ID.Base64URL, ParseBase64URL},
+	{"Hex", true, ID.Hex, ParseHex},
+	{"SortableBase32", true, ID.SortableBase32, ParseSortableBase32},
+	{"SortableBase62", true, ID.SortableBase62, ParseSortableBase62},
+	{"PaddedString", true, ID.PaddedString, ParsePaddedString},
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range codecs {
+		if _, err := c.parse(""); !errors.Is(err, ErrEmpty) {
+			t.Fatalf("Parse%s(\"\"): expected ErrEmpty, got %v", c.name, err)
+		}
+	}
+
+	tt := []struct {
+		name     string
+		parse    func(string) (ID, error)
+		s        string
+		expected error
+	}{
+		{"String", ParseString, "9223372036854775808", ErrOverflow},
+		{"Base2", ParseBase2, "1" + strings.Repeat("0", 63), ErrOverflow},
+		{"Base36", ParseBase36, "1y2p0ij32e8e8", ErrOverflow},
+		{"Hex", ParseHex, "8000000000000000", ErrOverflow},
+		{"String", ParseString, strings.Repeat("0", 5000) + "1", ErrInvalidLength},
+		{"String", ParseString, "-00000000000000000001", ErrInvalidLength},
+		{"Bytes", func(s string) (ID, error) { return ParseBytes([]byte(s)) }, strings.Repeat("0", 21), ErrInvalidLength},
+		{"Base2", ParseBase2, strings.Repeat("0", 65) + "1", ErrInvalidLength},
+		{"Base36", ParseBase36, strings.Repeat("0", 15), ErrInvalidLength},
+		{"Hex", ParseHex, "+0000000000000000f", ErrInvalidLength},
+		{"Base32", ParseBase32String, "!", ErrInvalidBase32},
+		{"Base32", ParseBase32String, strings.Repeat("y", 14), ErrInvalidLength},
+		{"Base32", ParseBase32String, strings.Repeat("9", 13), ErrOverflow},
+		{"Base58", ParseBase58String, "0", ErrInvalidBase58},
+		{"Base58", ParseBase58String, strings.Repeat("1", 12), ErrInvalidLength},
+		{"Base58", ParseBase58String, strings.Repeat("Z", 11), ErrOverflow},
+		{"Base62", ParseBase62, strings.Repeat("0", 12), ErrInvalidLength},
+		{"Base62", ParseBase62, strings.Repeat("z", 11), ErrOverflow},
+		{"Base32Crockford", ParseBase32Crockford, "-", ErrEmpty},
+		{"Base32Crockford", ParseBase32Crockford, strings.Repeat("0", 14), ErrInvalidLength},
+		{"Base32Crockford", ParseBase32Crockford, strings.Repeat("Z", 13), ErrOverflow},
+		{"Base64", ParseBase64, strings.Repeat("MTEx", 8), ErrInvalidLength},
+		{"Base64", ParseBase64, "OTIyMzM3MjAzNjg1NDc3NTgwOA==", ErrOverflow},
+	}
+	for _, tc := range tt {
+		if _, err := tc.parse(tc.s); !errors.Is(err, tc.expected) {
+			t.Fatalf("Parse%s(%q): expected %v, got %v", tc.name, tc.s, tc.expected, err)
+		}
+	}
+
+	// 最大长度的编码仍可转化
+	for _, tc := range []struct {
+		name  string
+		parse func(string) (ID, error)
+		s     string
+	}{
+		{"String", ParseString, "-9223372036854775808"},
+		{"Base2", ParseBase2, "-1" + strings.Repeat("0", 63)},
+		{"Base36", ParseBase36, "-1y2p0ij32e8e8"},
+		{"Hex", ParseHex, "-8000000000000000"},
+	} {
+		if id, err := tc.parse(tc.s); err != nil || id != math.MinInt64 {
+			t.Fatalf("Parse%s(%q) = %d, %v, expected %d", tc.name, tc.s, id, err, int64(math.MinInt64))
+		}
+	}
+
+	var id ID
+	if err := id.UnmarshalJSON([]byte(`"9223372036854775808"`)); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("UnmarshalJSON: expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestUnsignedNegative(t *testing.T) {
+	tt := []struct {
+		name     string
+		encode   func(ID) string
+		parse    func(string) (ID, error)
+		expected string
+	}{
+		{"Base32", ID.Base32, ParseBase32String, "x999999999999"},
+		{"Base58", ID.Base58, ParseBase58String, "JPwcyDCgEup"},
+		{"Base62", ID.Base62, ParseBase62, "LygHa16AHYF"},
+		{"Base32Crockford", ID.Base32Crockford, ParseBase32Crockford, "FZZZZZZZZZZZZ"},
+	}
+	for _, tc := range tt {
+		s := tc.encode(-1)
+		if s != tc.expected {
+			t.Fatalf("ID(-1).%s() = %q, expected %q", tc.name, s, tc.expected)
+		}
+		if _, err := tc.parse(s); !errors.Is(err, ErrOverflow) {
+			t.Fatalf("Parse%s(%q): expected ErrOverflow, got %v", tc.name, s, err)
+		}
+	}
+}
+
+func TestParseStrconvErrors(t *testing.T) {
+	tt := []struct {
+		name     string
+		parse    func(string) (ID, error)
+		s        string
+		expected error
+		compat   error
+	}{
+		{"String", ParseString, "", ErrEmpty, strconv.ErrSyntax},
+		{"String", ParseString, "9223372036854775808", ErrOverflow, strconv.ErrRange},
+		{"Base2", ParseBase2, "", ErrEmpty, strconv.ErrSyntax},
+		{"Base2", ParseBase2, "1" + strings.Repeat("0", 63), ErrOverflow, strconv.ErrRange},
+		{"Base36", ParseBase36, "1y2p0ij32e8e8", ErrOverflow, strconv.ErrRange},
+		{"Hex", ParseHex, "-8000000000000001", ErrOverflow, strconv.ErrRange},
+	}
+	for _, tc := range tt {
+		_, err := tc.parse(tc.s)
+		if !errors.Is(err, tc.expected) || !errors.Is(err, tc.compat) {
+			t.Fatalf("Parse%s(%q): expected %v and %v, got %v", tc.name, tc.s, tc.expected, tc.compat, err)
+		}
+		var numErr *strconv.NumError
+		if !errors.As(err, &numErr) {
+			t.Fatalf("Parse%s(%q): expected *strconv.NumError, got %T", tc.name, tc.s, err)
+		}
+		// 错误信息与 strconv.ParseInt 相同
+		if _, sErr := strconv.ParseInt(tc.s, 10, 64); tc.name == "String" && err.Error() != sErr.Error() {
+			t.Fatalf("ParseString(%q) error %q, expected %q", tc.s, err, sErr)
+		}
+	}
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	for _, id := range codecIDs {
+		f.Add(int64(id))
+	}
+	f.Add(int64(-1))
+	f.Add(int64(math.MinInt64))
+	f.Fuzz(func(t *testing.T, i int64) {
+		for _, c := range codecs {
+			id := ID(i)
+			if !c.signed && id < 0 {
+				id = -id - 1
+			}
+			s := c.encode(id)
+			pID, err := c.parse(s)
+			if err != nil || pID != id {
+				t.Fatalf("Parse%s(%q) = %d, %v, expected %d", c.name, s, pID, err, id)
+			}
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("")
+	f.Add("0")
+	f.Add("-1")
+	f.Add("1116766490855473152")
+	f.Add("zzzzzzzzzzzzzzzzzzzz")
+	f.Add("MTExNjgxOTQ5NDY2MDk5NzEyMA==")
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, c := range codecs {
+			id, err := c.parse(s)
+			if err != nil {
+				continue
+			}
+			if !c.signed && id < 0 {
+				t.Fatalf("Parse%s(%q) = %d, expected non-negative", c.name, s, id)
+			}
+			// 解码成功的值重新编码后应解码为相同值
+			if pID, err := c.parse(c.encode(id)); err != nil || pID != id {
+				t.Fatalf("Parse%s(%s(%d)) = %d, %v", c.name, c.name, id, pID, err)
+			}
+		}
+	})
+}
+
+func TestZeroAlloc(t *testing.T) {
+	id := ID(1116766490855473152)
+	buf := make([]byte, 0, 64)
+	b32, b58, b64 := id.AppendBase32(nil), id.AppendBase58(nil), id.AppendBase64(nil)
+	dec, hex, b64u := id.AppendString(nil), id.AppendHex(nil), id.Base64URL()
+
+	tt := map[string]func(){
+		"AppendString":      func() { id.AppendString(buf) },
+		"AppendBase32":      func() { id.AppendBase32(buf) },
+		"AppendBase58":      func() { id.AppendBase58(buf) },
+		"AppendBase64":      func() { id.AppendBase64(buf) },
+		"AppendHex":         func() { id.AppendHex(buf) },
+		"ParseBytes":        func() { ParseBytes(dec) },
+		"ParseBase32":       func() { ParseBase32(b32) },
+		"ParseBase32String": func() { ParseBase32String(string(b32)) },
+		"ParseBase58":       func() { ParseBase58(b58) },
+		"ParseBase64Bytes":  func() { ParseBase64Bytes(b64) },
+		"ParseHexBytes":     func() { ParseHexBytes(hex) },
+		"AppendBase64URL":   func() { id.AppendBase64URL(buf) },
+		"ParseBase64URL":    func() { ParseBase64URL(b64u) },
+	}
+	for name, fn := range tt {
+		if n := testing.AllocsPerRun(100, fn); n != 0 {
+			t.Errorf("%s allocates %v times", name, n)
+		}
+	}
+}
+
+func TestBase62(t *testing.T) {
+	sf := MustNew(Node(1))
+	for _, id := range append(codecIDs, sf.ID()) {
+		b62 := id.Base62()
+		pID, err := ParseBase62(b62)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pID != id {
+			t.Fatalf("pID %v != id %v", pID, id)
+		}
+	}
+	if s := ID(61).Base62(); s != "z" {
+		t.Fatalf("Base62(61) = %q, expected \"z\"", s)
+	}
+	if s := ID(math.MaxInt64).Base62(); s != "AzL8n0Y58m7" {
+		t.Fatalf("Base62(MaxInt64) = %q, expected \"AzL8n0Y58m7\"", s)
+	}
+	if _, err := ParseBase62("abc-"); err != ErrInvalidBase62 {
+		t.Fatalf("Expected ErrInvalidBase62, got %v", err)
+	}
+}
+
+func TestBase64URL(t *testing.T) {
+	sf := MustNew(Node(1))
+	for _, id := range append(codecIDs, sf.ID(), -1) {
+		s := id.Base64URL()
+		if len(s) != Base64URLLen {
+			t.Fatalf("Base64URL(%d) = %q, expected length %d", id, s, Base64URLLen)
+		}
+		pID, err := ParseBase64URL(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pID != id {
+			t.Fatalf("pID %v != id %v", pID, id)
+		}
+	}
+	if s := ID(13587).Base64URL(); s != "AAAAAAAANRM" {
+		t.Fatalf("Base64URL(13587) = %q, expected \"AAAAAAAANRM\"", s)
+	}
+	// 兼容原 Base64 格式
+	if s := ID(13587).Base64(); s != "MTM1ODc=" {
+		t.Fatalf("Base64(13587) = %q, expected \"MTM1ODc=\"", s)
+	}
+	if _, err := ParseBase64URL("AAAAAAAANR"); err != ErrInvalidLength {
+		t.Fatalf("Expected ErrInvalidLength, got %v", err)
+	}
+	if _, err := ParseBase64URL("AAAAAAAAN+M"); err == nil {
+		t.Fatal("no error parsing standard base64 alphabet")
+	}
+	// 末尾字符的 2 个填充位必须为 0
+	if _, err := ParseBase64URL("AAAAAAAANRN"); err == nil {
+		t.Fatal("no error parsing non-zero padding bits")
+	}
+	if _, err := ParseBase64URL("AAAAAAAAN\nR"); err == nil {
+		t.Fatal("no error parsing embedded newline")
+	}
+}
+
+func TestHex(t *testing.T) {
+	id := ID(1116766490855473152)
+	if h := id.Hex(); h != "f7f8d34e0800000" {
+		t.Fatalf("Hex() = %q", h)
+	}
+	for _, s := range []string{"f7f8d34e0800000", "F7F8D34E0800000"} {
+		pID, err := ParseHex(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pID != id {
+			t.Fatalf("pID %v != id %v", pID, id)
+		}
+	}
+	if _, err := ParseHex("f7g"); err == nil {
+		t.Fatal("no error parsing f7g")
+	}
+}
+
+func TestBase32Crockford(t *testing.T) {
+	sf := MustNew(Node(1))
+	for _, id := range append(codecIDs, sf.ID()) {
+		c := id.Base32Crockford()
+		pID, err := ParseBase32Crockford(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pID != id {
+			t.Fatalf("pID %v != id %v", pID, id)
+		}
+	}
+
+	tt := []struct {
+		s        string
+		expected ID
+	}{
+		{"10", 32},
+		{"1o", 32},
+		{"IO", 32},
+		{"l0", 32},
+		{"ZZ", 1023},
+		{"zz", 1023},
+		{"Z-Z", 1023},
+	}
+	for _, tc := range tt {
+		pID, err := ParseBase32Crockford(tc.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pID != tc.expected {
+			t.Fatalf("ParseBase32Crockford(%q) = %d, expected %d", tc.s, pID, tc.expected)
+		}
+	}
+	for _, s := range []string{"U", "u", "1!"} {
+		if _, err := ParseBase32Crockford(s); err != ErrInvalidCrockford {
+			t.Fatalf("Expected ErrInvalidCrockford parsing %q, got %v", s, err)
+		}
+	}
+}
+
+func FuzzBase62(f *testing.F) {
+	for _, id := range codecIDs {
+		f.Add(int64(id))
+	}
+	f.Fuzz(func(t *testing.T, i int64) {
+		id := ID(i)
+		if id < 0 {
+			id = -id - 1
+		}
+		pID, err := ParseBase62(id.Base62())
+		if err != nil || pID != id {
+			t.Fatalf("ParseBase62(%q) = %d, %v, expected %d", id.Base62(), pID, err, id)
+		}
+		if pID, _ := ParseBase62Bytes(id.AppendBase62(nil)); pID != id {
+			t.Fatalf("ParseBase62Bytes = %d, expected %d", pID, id)
+		}
+	})
+}
+
+func FuzzHex(f *testing.F) {
+	for _, id := range codecIDs {
+		f.Add(int64(id))
+	}
+	f.Fuzz(func(t *testing.T, i int64) {
+		id := ID(i)
+		pID, err := ParseHex(id.Hex())
+		if err != nil || pID != id {
+			t.Fatalf("ParseHex(%q) = %d, %v, expected %d", id.Hex(), pID, err, id)
+		}
+		if pID, _ := ParseHexBytes(id.AppendHex(nil)); pID != id {
+			t.Fatalf("ParseHexBytes = %d, expected %d", pID, id)
+		}
+	})
+}
+
+func FuzzBase32Crockford(f *testing.F) {
+	for _, id := range codecIDs {
+		f.Add(int64(id))
+	}
+	f.Fuzz(func(t *testing.T, i int64) {
+		id := ID(i)
+		if id < 0 {
+			id = -id - 1
+		}
+		c := id.Base32Crockford()
+		pID, err := ParseBase32Crockford(c)
+		if err != nil || pID != id {
+			t.Fatalf("ParseBase32Crockford(%q) = %d, %v, expected %d", c, pID, err, id)
+		}
+		if pID, _ := ParseBase32Crockford(strings.ToLower(c)); pID != id {
+			t.Fatalf("ParseBase32Crockford(%q) = %d, expected %d", strings.ToLower(c), pID, id)
+		}
+	})
+}
+
+func BenchmarkAppendBase32(b *testing.B) {
+	sf, _ := New(Node(1))
+	id := sf.ID()
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		id.AppendBase32(buf)
+	}
+}
+
+func BenchmarkAppendBase58(b *testing.B) {
+	sf, _ := New(Node(1))
+	id := sf.ID()
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		id.AppendBase58(buf)
+	}
+}
+
+func BenchmarkAppendBase64(b *testing.B) {
+	sf, _ := New(Node(1))
+	id := sf.ID()
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		id.AppendBase64(buf)
+	}
+}
+
+func BenchmarkAppendString(b *testing.B) {
+	sf, _ := New(Node(1))
+	id := sf.ID()
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		id.AppendString(buf)
+	}
+}
+
+func BenchmarkParseBase32String(b *testing.B) {
+	sf, _ := New(Node(1))
+	b32 := sf.ID().Base32()
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParseBase32String(b32)
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	sf, _ := New(Node(1))
+	dec := sf.ID().Bytes()
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParseBytes(dec)
+	}
+}
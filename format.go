@@ -0,0 +1,174 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+)
+
+// Format ID 编码格式
+type Format uint8
+
+const (
+	FormatUnknown Format = iota
+	FormatDecimal
+	FormatBase2
+	FormatBase32
+	FormatBase32Crockford
+	FormatBase36
+	FormatBase58
+	FormatBase62
+	FormatBase64
+	FormatBase64URL
+	FormatHex
+)
+
+var (
+	// ErrUnknownFormat is returned by ParseFormat and ParseAny when given an unknown format or prefix tag
+	ErrUnknownFormat = errors.New("unknown format")
+	// ErrFormatNotAllowed is returned by ParseAny when the prefix tag names a format not in the allowed list
+	ErrFormatNotAllowed = errors.New("format not allowed")
+	// ErrNoFormat is returned by ParseAny when the input is not valid in any allowed format
+	ErrNoFormat = errors.New("no format matched")
+)
+
+// DefaultFormats ParseAny 默认尝试的格式及优先级
+var DefaultFormats = []Format{FormatDecimal, FormatBase58, FormatBase32, FormatBase36, FormatBase64}
+
+// Formats 返回所有编码格式, DefaultFormats 中的格式在前并保持其优先级
+// 用于 ParseAny 时接受任意前缀标签, 无前缀时按 DefaultFormats 优先识别
+func Formats() []Format {
+	formats := make([]Format, 0, len(formatInfo)-1)
+	formats = append(formats, DefaultFormats...)
+	for f := FormatDecimal; int(f) < len(formatInfo); f++ {
+		if !hasFormat(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// formatInfo 格式名称, 前缀标签及编解码函数
+var formatInfo = [...]struct {
+	name   string
+	tag    string
+	encode func(ID) string
+	parse  func(string) (ID, error)
+}{
+	FormatUnknown:         {name: "unknown"},
+	FormatDecimal:         {"decimal", "dec", ID.String, ParseString},
+	FormatBase2:           {"base2", "b2", ID.Base2, ParseBase2},
+	FormatBase32:          {"base32", "b32", ID.Base32, ParseBase32String},
+	FormatBase32Crockford: {"crockford", "c32", ID.Base32Crockford, ParseBase32Crockford},
+	FormatBase36:          {"base36", "b36", ID.Base36, ParseBase36},
+	FormatBase58:          {"base58", "b58", ID.Base58, ParseBase58String},
+	FormatBase62:          {"base62", "b62", ID.Base62, ParseBase62},
+	FormatBase64:          {"base64", "b64", ID.Base64, ParseBase64},
+	FormatBase64URL:       {"base64url", "b64u", ID.Base64URL, ParseBase64URL},
+	FormatHex:             {"hex", "hex", ID.Hex, ParseHex},
+}
+
+// String 返回格式名称
+func (f Format) String() string {
+	if int(f) >= len(formatInfo) {
+		return formatInfo[FormatUnknown].name
+	}
+	return formatInfo[f].name
+}
+
+// Tag 返回格式前缀标签, 如 Base58 为 b58
+func (f Format) Tag() string {
+	if f == FormatUnknown || int(f) >= len(formatInfo) {
+		return ""
+	}
+	return formatInfo[f].tag
+}
+
+// Encode 使用该格式编码 ID
+func (f Format) Encode(id ID) string {
+	if f == FormatUnknown || int(f) >= len(formatInfo) {
+		return ""
+	}
+	return formatInfo[f].encode(id)
+}
+
+// Parse 使用该格式解码字符串到 ID 类型
+func (f Format) Parse(s string) (ID, error) {
+	if f == FormatUnknown || int(f) >= len(formatInfo) {
+		return -1, ErrUnknownFormat
+	}
+	return formatInfo[f].parse(s)
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口, 编码为格式名称
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口, 接受格式名称或前缀标签
+func (f *Format) UnmarshalText(b []byte) error {
+	v, err := ParseFormat(string(b))
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// ParseFormat 根据名称或前缀标签返回格式, 不区分大小写
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(name)
+	for f := FormatDecimal; int(f) < len(formatInfo); f++ {
+		if formatInfo[f].name == name || formatInfo[f].tag == name {
+			return f, nil
+		}
+	}
+	return FormatUnknown, ErrUnknownFormat
+}
+
+// ParseAny 自动识别编码格式转化字符串到 ID 类型, 返回识别的格式
+// 带有前缀标签 (如 b58:3Yx9...) 时直接使用对应格式解码;
+// 否则按 formats 顺序依次尝试, 返回第一个解码成功的结果, formats 为空时使用 DefaultFormats
+// 无前缀时多种格式可能同时有效 (如纯数字), 由顺序决定优先级
+func ParseAny(s string, formats ...Format) (ID, Format, error) {
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+	if i := strings.IndexByte(s, ':'); i > 0 {
+		f, err := ParseFormat(s[:i])
+		if err != nil {
+			return -1, FormatUnknown, err
+		}
+		if !hasFormat(formats, f) {
+			return -1, FormatUnknown, ErrFormatNotAllowed
+		}
+		id, err := f.Parse(s[i+1:])
+		if err != nil {
+			return -1, FormatUnknown, err
+		}
+		return id, f, nil
+	}
+
+	if s == "" {
+		return -1, FormatUnknown, ErrEmpty
+	}
+	for _, f := range formats {
+		if id, err := f.Parse(s); err == nil {
+			return id, f, nil
+		}
+	}
+	return -1, FormatUnknown, ErrNoFormat
+}
+
+// Tagged 返回带有格式前缀标签的编码字符串, 如 b58:3Yx9..., 可使用 ParseAny 解码
+func (f ID) Tagged(format Format) string {
+	return format.Tag() + ":" + format.Encode(f)
+}
+
+func hasFormat(formats []Format, f Format) bool {
+	for _, v := range formats {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
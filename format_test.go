@@ -0,0 +1,111 @@
+package snowflake
+
+import "testing"
+
+func TestParseFormat(t *testing.T) {
+	for f := FormatDecimal; f <= FormatHex; f++ {
+		for _, name := range []string{f.String(), f.Tag()} {
+			pf, err := ParseFormat(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pf != f {
+				t.Fatalf("ParseFormat(%q) = %v, expected %v", name, pf, f)
+			}
+		}
+	}
+	if f, err := ParseFormat("B58"); err != nil || f != FormatBase58 {
+		t.Fatalf("ParseFormat(\"B58\") = %v, %v", f, err)
+	}
+	if _, err := ParseFormat("base99"); err != ErrUnknownFormat {
+		t.Fatalf("Expected ErrUnknownFormat, got %v", err)
+	}
+	if s := Format(200).String(); s != "unknown" {
+		t.Fatalf("Format(200).String() = %q", s)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	formats := Formats()
+	if len(formats) != int(FormatHex) {
+		t.Fatalf("Formats() = %v, expected %d formats", formats, FormatHex)
+	}
+	for i, f := range DefaultFormats {
+		if formats[i] != f {
+			t.Fatalf("Formats()[%d] = %v, expected %v", i, formats[i], f)
+		}
+	}
+	for f := FormatDecimal; f <= FormatHex; f++ {
+		if !hasFormat(formats, f) {
+			t.Fatalf("Formats() missing %v", f)
+		}
+		b, err := f.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pf Format
+		if err := pf.UnmarshalText(b); err != nil || pf != f {
+			t.Fatalf("UnmarshalText(%q) = %v, %v", b, pf, err)
+		}
+	}
+	var f Format
+	if err := f.UnmarshalText([]byte("base99")); err != ErrUnknownFormat {
+		t.Fatalf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	id := MustNew(Node(1)).ID()
+
+	// 前缀标签
+	for f := FormatDecimal; f <= FormatHex; f++ {
+		s := id.Tagged(f)
+		pID, pf, err := ParseAny(s, f)
+		if err != nil {
+			t.Fatalf("ParseAny(%q) error %s", s, err)
+		}
+		if pID != id || pf != f {
+			t.Fatalf("ParseAny(%q) = %d, %v, expected %d, %v", s, pID, pf, id, f)
+		}
+	}
+
+	// 默认优先级
+	tt := []struct {
+		s        string
+		expected Format
+	}{
+		{id.String(), FormatDecimal},
+		{id.Base58(), FormatBase58},
+		{id.Base64(), FormatBase64},
+	}
+	for _, tc := range tt {
+		pID, pf, err := ParseAny(tc.s)
+		if err != nil {
+			t.Fatalf("ParseAny(%q) error %s", tc.s, err)
+		}
+		if pID != id || pf != tc.expected {
+			t.Fatalf("ParseAny(%q) = %d, %v, expected %d, %v", tc.s, pID, pf, id, tc.expected)
+		}
+	}
+
+	// 指定格式列表
+	b32 := id.Base32()
+	if pID, pf, err := ParseAny(b32, FormatBase32); err != nil || pID != id || pf != FormatBase32 {
+		t.Fatalf("ParseAny(%q, FormatBase32) = %d, %v, %v", b32, pID, pf, err)
+	}
+	if _, _, err := ParseAny(id.Base58(), FormatDecimal); err != ErrNoFormat {
+		t.Fatalf("Expected ErrNoFormat, got %v", err)
+	}
+	if _, _, err := ParseAny(id.Tagged(FormatHex)); err != ErrFormatNotAllowed {
+		t.Fatalf("Expected ErrFormatNotAllowed, got %v", err)
+	}
+	if _, _, err := ParseAny("b99:123"); err != ErrUnknownFormat {
+		t.Fatalf("Expected ErrUnknownFormat, got %v", err)
+	}
+	if _, _, err := ParseAny("b58:0"); err != ErrInvalidBase58 {
+		t.Fatalf("Expected ErrInvalidBase58, got %v", err)
+	}
+	if _, _, err := ParseAny(""); err != ErrEmpty {
+		t.Fatalf("Expected ErrEmpty, got %v", err)
+	}
+}
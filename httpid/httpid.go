@@ -0,0 +1,223 @@
+// Package httpid 通过 HTTP 提供 Snowflake ID 服务
+//
+// Handler 提供以下接口, 仅支持 GET 及 HEAD 请求:
+//
+//	GET /id           生成 1 个 ID
+//	GET /ids?n=10     生成 n 个 ID
+//	GET /decode/{id}  解码 ID 的时间, 节点及序列值
+//	GET /layout       ID 布局信息
+//	GET /healthz      健康检查
+//
+// 查询参数 format 选择 ID 编码格式 (名称或前缀标签, 如 base58, b58), 默认十进制;
+// 对于 /decode/{id} 则为输入 ID 的编码格式, 默认自动识别.
+// 查询参数 output=json 或 Accept: application/json 时返回 JSON, 否则返回纯文本.
+package httpid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+const DefaultMaxBatch = 1000 // /ids 默认单次最多生成的 ID 数量
+
+// Options 配置项
+type Options struct {
+	maxBatch int // /ids 单次最多生成的 ID 数量
+}
+
+type Option func(*Options)
+
+// MaxBatch 设置 /ids 单次最多生成的 ID 数量
+func MaxBatch(n int) Option {
+	return func(o *Options) {
+		o.maxBatch = n
+	}
+}
+
+// Handler HTTP ID 服务
+type Handler struct {
+	sf       *snowflake.Snowflake
+	layout   snowflake.Layout
+	maxBatch int
+}
+
+// NewHandler 创建使用 sf 生成 ID 的 HTTP 服务
+func NewHandler(sf *snowflake.Snowflake, opts ...Option) *Handler {
+	options := Options{maxBatch: DefaultMaxBatch}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.maxBatch <= 0 {
+		options.maxBatch = DefaultMaxBatch
+	}
+	return &Handler{sf: sf, layout: sf.Layout(), maxBatch: options.maxBatch}
+}
+
+// ServeHTTP 实现 http.Handler 接口
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, r, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	path := r.URL.Path
+	switch {
+	case path == "/id":
+		h.serveIDs(w, r, 1, false)
+	case path == "/ids":
+		n, err := strconv.Atoi(r.URL.Query().Get("n"))
+		if err != nil || n < 1 || n > h.maxBatch {
+			writeError(w, r, http.StatusBadRequest, fmt.Errorf("n must be between 1 and %d", h.maxBatch))
+			return
+		}
+		h.serveIDs(w, r, n, true)
+	case strings.HasPrefix(path, "/decode/") && len(path) > len("/decode/"):
+		h.serveDecode(w, r, path[len("/decode/"):])
+	case path == "/layout":
+		h.serveLayout(w, r)
+	case path == "/healthz":
+		writeText(w, http.StatusOK, "ok\n")
+	default:
+		writeError(w, r, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// serveIDs 生成 n 个 ID, list 为 true 时 JSON 响应使用 ids 数组
+func (h *Handler) serveIDs(w http.ResponseWriter, r *http.Request, n int, list bool) {
+	f, err := idFormat(r, snowflake.FormatDecimal)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	var ids []snowflake.ID
+	if n == 1 {
+		ids = []snowflake.ID{h.sf.ID()}
+	} else {
+		ids = h.sf.Batch(n)
+	}
+
+	encoded := make([]string, len(ids))
+	for i, id := range ids {
+		encoded[i] = f.Encode(id)
+	}
+	if !wantJSON(r) {
+		writeText(w, http.StatusOK, strings.Join(encoded, "\n")+"\n")
+		return
+	}
+	if list {
+		writeJSON(w, http.StatusOK, struct {
+			IDs []string `json:"ids"`
+		}{encoded})
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		ID string `json:"id"`
+	}{encoded[0]})
+}
+
+// serveDecode 解码 ID 的时间, 节点及序列值
+func (h *Handler) serveDecode(w http.ResponseWriter, r *http.Request, s string) {
+	var formats []snowflake.Format
+	if r.URL.Query().Get("format") != "" {
+		f, err := idFormat(r, snowflake.FormatUnknown)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		formats = []snowflake.Format{f}
+	}
+	d, err := h.layout.Decode(s, formats...)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, fmt.Errorf("invalid id %q: %w", s, err))
+		return
+	}
+
+	if wantJSON(r) {
+		writeJSON(w, http.StatusOK, d)
+		return
+	}
+	writeText(w, http.StatusOK, fmt.Sprintf("id=%d\nformat=%s\ntime=%s\nnode=%d\nseq=%d\n",
+		d.ID, d.Format, d.Time.Format(time.RFC3339Nano), d.Node, d.Seq))
+}
+
+// serveLayout ID 布局信息, 纯文本时返回 Snowflake.Banner
+func (h *Handler) serveLayout(w http.ResponseWriter, r *http.Request) {
+	if !wantJSON(r) {
+		writeText(w, http.StatusOK, h.sf.Banner()+"\n")
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		StartTime int64     `json:"start_time"`
+		TimeBits  uint8     `json:"time_bits"`
+		NodeBits  uint8     `json:"node_bits"`
+		SeqBits   uint8     `json:"seq_bits"`
+		Node      int64     `json:"node"`
+		MaxNode   int64     `json:"max_node"`
+		MaxSeq    int64     `json:"max_seq"`
+		Lifetime  time.Time `json:"lifetime"`
+	}{
+		StartTime: h.sf.StartTime(),
+		TimeBits:  h.sf.TimeBits(),
+		NodeBits:  h.sf.NodeBits(),
+		SeqBits:   h.sf.SeqBits(),
+		Node:      h.sf.Node(),
+		MaxNode:   h.sf.MaxNode(),
+		MaxSeq:    h.sf.MaxSeq(),
+		Lifetime:  h.sf.Lifetime().UTC(),
+	})
+}
+
+// idFormat 返回查询参数 format 指定的编码格式, 未指定时返回 def
+func idFormat(r *http.Request, def snowflake.Format) (snowflake.Format, error) {
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		return def, nil
+	}
+	f, err := snowflake.ParseFormat(name)
+	if err != nil {
+		return f, fmt.Errorf("format %q: %w", name, err)
+	}
+	return f, nil
+}
+
+// wantJSON 请求是否需要 JSON 响应
+func wantJSON(r *http.Request) bool {
+	switch r.URL.Query().Get("output") {
+	case "json":
+		return true
+	case "text":
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeText(w http.ResponseWriter, code int, s string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	w.Write([]byte(s))
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, code int, err error) {
+	if wantJSON(r) {
+		writeJSON(w, code, struct {
+			Error string `json:"error"`
+		}{err.Error()})
+		return
+	}
+	writeText(w, code, err.Error()+"\n")
+}
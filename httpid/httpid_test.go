@@ -0,0 +1,175 @@
+package httpid
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+func newTestServer(t *testing.T, opts ...Option) (*httptest.Server, *snowflake.Snowflake) {
+	t.Helper()
+	sf := snowflake.MustNew(snowflake.Node(3), snowflake.NodeBits(16), snowflake.SeqBits(6))
+	srv := httptest.NewServer(NewHandler(sf, opts...))
+	t.Cleanup(srv.Close)
+	return srv, sf
+}
+
+func get(t *testing.T, url, accept string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(b)
+}
+
+func TestID(t *testing.T) {
+	srv, sf := newTestServer(t)
+
+	resp, body := get(t, srv.URL+"/id", "")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Fatalf("status = %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	id, err := snowflake.ParseString(strings.TrimSpace(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node := sf.Layout().Node(id); node != 3 {
+		t.Fatalf("node = %d, want 3", node)
+	}
+
+	resp, body = get(t, srv.URL+"/id?format=b58", "application/json")
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+	}
+	var v struct{ ID string }
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		t.Fatal(err)
+	}
+	next, err := snowflake.ParseBase58String(v.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next <= id {
+		t.Fatalf("id %d is not greater than %d", next, id)
+	}
+}
+
+func TestIDs(t *testing.T) {
+	srv, _ := newTestServer(t, MaxBatch(100))
+
+	_, body := get(t, srv.URL+"/ids?n=100&output=json", "")
+	var v struct{ IDs []snowflake.ID }
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.IDs) != 100 {
+		t.Fatalf("got %d ids, want 100", len(v.IDs))
+	}
+	for i := 1; i < len(v.IDs); i++ {
+		if v.IDs[i] <= v.IDs[i-1] {
+			t.Fatalf("id %d is not greater than %d", v.IDs[i], v.IDs[i-1])
+		}
+	}
+
+	_, body = get(t, srv.URL+"/ids?n=3&format=hex", "application/json")
+	if !strings.Contains(body, `"ids":[`) {
+		t.Fatalf("body = %q", body)
+	}
+	_, body = get(t, srv.URL+"/ids?n=3&format=hex&output=text", "application/json")
+	if lines := strings.Fields(body); len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %q", len(lines), body)
+	}
+
+	for _, q := range []string{"", "n=0", "n=101", "n=x", "n=2&format=xml"} {
+		resp, body := get(t, srv.URL+"/ids?"+q, "application/json")
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(body, `"error"`) {
+			t.Fatalf("%s: status = %d, body %q", q, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	srv, sf := newTestServer(t)
+	id, _ := sf.Layout().Compose(time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC), 7, 9)
+
+	_, body := get(t, srv.URL+"/decode/"+id.Tagged(snowflake.FormatBase62), "application/json")
+	want := `{"id":"` + id.String() + `","format":"base62","time":"2024-01-02T03:04:05.006Z","node":7,"seq":9}` + "\n"
+	if body != want {
+		t.Fatalf("body = %q, want %q", body, want)
+	}
+
+	_, body = get(t, srv.URL+"/decode/"+id.Hex()+"?format=hex", "")
+	want = "id=" + id.String() + "\nformat=hex\ntime=2024-01-02T03:04:05.006Z\nnode=7\nseq=9\n"
+	if body != want {
+		t.Fatalf("body = %q, want %q", body, want)
+	}
+
+	for _, path := range []string{"/decode/!!", "/decode/123?format=xml", "/decode/2?format=base2"} {
+		if resp, body := get(t, srv.URL+path, ""); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, body %q", path, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	srv, sf := newTestServer(t)
+
+	_, body := get(t, srv.URL+"/layout", "")
+	if body != sf.Banner()+"\n" {
+		t.Fatalf("body = %q", body)
+	}
+
+	_, body = get(t, srv.URL+"/layout?output=json", "")
+	var v struct {
+		NodeBits uint8 `json:"node_bits"`
+		SeqBits  uint8 `json:"seq_bits"`
+		Node     int64 `json:"node"`
+	}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.NodeBits != 16 || v.SeqBits != 6 || v.Node != 3 {
+		t.Fatalf("layout = %+v", v)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	if resp, body := get(t, srv.URL+"/healthz", ""); resp.StatusCode != http.StatusOK || body != "ok\n" {
+		t.Fatalf("healthz status = %d, body %q", resp.StatusCode, body)
+	}
+	for _, path := range []string{"/", "/decode/", "/ids/1", "/idx"} {
+		if resp, _ := get(t, srv.URL+path, ""); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("%s: status = %d, want 404", path, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Post(srv.URL+"/id", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "GET, HEAD" {
+		t.Fatalf("POST status = %d, Allow %q", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+}
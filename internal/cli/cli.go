@@ -0,0 +1,56 @@
+// Package cli 命令行工具共用的参数处理
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/teamlint/snowflake"
+)
+
+// LayoutFlags ID 布局参数
+type LayoutFlags struct {
+	startTime int64
+	nodeBits  uint
+	seqBits   uint
+}
+
+// AddLayoutFlags 注册布局参数 start, node-bits 及 seq-bits, prefix 为参数名前缀, 如 from-
+func AddLayoutFlags(fs *flag.FlagSet, prefix string) *LayoutFlags {
+	var l LayoutFlags
+	fs.Int64Var(&l.startTime, prefix+"start", snowflake.DefaultStartTime, "layout start time in epoch milliseconds")
+	fs.UintVar(&l.nodeBits, prefix+"node-bits", uint(snowflake.DefaultNodeBits), "layout node bits")
+	fs.UintVar(&l.seqBits, prefix+"seq-bits", uint(snowflake.DefaultSeqBits), "layout sequence bits")
+	return &l
+}
+
+// Options 校验参数并返回对应的配置项
+func (l *LayoutFlags) Options() ([]snowflake.Option, error) {
+	// 先于 uint8 转化检查, 避免溢出后通过 New 的位数检查
+	if l.nodeBits+l.seqBits > uint(snowflake.MaxNotTimeBits) {
+		return nil, fmt.Errorf("sum(%d) of node bits and sequence bits must be less than %d", l.nodeBits+l.seqBits, snowflake.MaxNotTimeBits)
+	}
+	return []snowflake.Option{
+		snowflake.StartTime(l.startTime),
+		snowflake.NodeBits(uint8(l.nodeBits)),
+		snowflake.SeqBits(uint8(l.seqBits)),
+	}, nil
+}
+
+// Layout 返回 ID 布局
+func (l *LayoutFlags) Layout() (snowflake.Layout, error) {
+	opts, err := l.Options()
+	if err != nil {
+		return snowflake.Layout{}, err
+	}
+	return snowflake.NewLayout(opts...)
+}
+
+// New 使用布局参数及其他配置项创建生成器
+func (l *LayoutFlags) New(opts ...snowflake.Option) (*snowflake.Snowflake, error) {
+	lopts, err := l.Options()
+	if err != nil {
+		return nil, err
+	}
+	return snowflake.New(append(lopts, opts...)...)
+}
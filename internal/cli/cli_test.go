@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/teamlint/snowflake"
+)
+
+func TestLayoutFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	l := AddLayoutFlags(fs, "to-")
+	if err := fs.Parse([]string{"-to-start", "1577836800000", "-to-node-bits", "16", "-to-seq-bits", "6"}); err != nil {
+		t.Fatal(err)
+	}
+	layout, err := l.Layout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.StartTime() != 1577836800000 || layout.NodeBits() != 16 || layout.SeqBits() != 6 {
+		t.Fatalf("layout = %+v", layout)
+	}
+	sf, err := l.New(snowflake.Node(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sf.Node() != 9 || sf.NodeBits() != 16 {
+		t.Fatalf("node = %d, node bits = %d", sf.Node(), sf.NodeBits())
+	}
+
+	// 256 转化为 uint8 时溢出为 0, 需在转化前拒绝
+	for _, args := range [][]string{{"-to-node-bits", "256"}, {"-to-node-bits", "20", "-to-seq-bits", "10"}} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		l := AddLayoutFlags(fs, "to-")
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.Layout(); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+		if _, err := l.New(); err == nil {
+			t.Fatalf("%v: expected error", args)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package snowflake
+
+import "strconv"
+
+//********************************************************************************
+// JSON
+
+// IDNumber 以 JSON 数字形式编码的 ID, 适用于要求数字 ID 的服务
+// 注意 JavaScript 等语言无法精确表示超过 53 位的整数
+type IDNumber ID
+
+// ID 返回 ID 类型值
+func (n IDNumber) ID() ID {
+	return ID(n)
+}
+
+// String 返回字符串类型 ID
+func (n IDNumber) String() string {
+	return ID(n).String()
+}
+
+// MarshalJSON 编码为 JSON 数字
+func (n IDNumber) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(make([]byte, 0, 20), int64(n), 10), nil
+}
+
+// UnmarshalJSON 转化 JSON 字节数组到 IDNumber 类型, 同时支持字符串及数字形式
+func (n *IDNumber) UnmarshalJSON(b []byte) error {
+	id, err := parseJSON(b)
+	if err != nil {
+		return err
+	}
+	*n = IDNumber(id)
+	return nil
+}
+
+// MarshalJSON NullID 有效时编码为 JSON 字符串, 否则为 null
+func (n NullID) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.ID.MarshalJSON()
+}
+
+// UnmarshalJSON 转化 JSON 字节数组到 NullID 类型, null 为无效值
+func (n *NullID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	id, err := parseJSON(b)
+	if err != nil {
+		return err
+	}
+	n.ID, n.Valid = id, true
+	return nil
+}
+
+// parseJSON 转化 JSON 字符串 "123" 或数字 123 到 ID 类型
+func parseJSON(b []byte) (ID, error) {
+	i, end := 0, len(b)
+	quoted := len(b) > 0 && b[0] == '"'
+	if quoted {
+		i++
+		end--
+		if end < i || b[end] != '"' {
+			return 0, JSONSyntaxError{original: b, Offset: len(b)}
+		}
+	}
+
+	start := i
+	if i < end && b[i] == '-' {
+		i++
+	}
+	if i == end {
+		return 0, JSONSyntaxError{original: b, Offset: i}
+	}
+	for ; i < end; i++ {
+		if b[i] < '0' || b[i] > '9' {
+			return 0, JSONSyntaxError{original: b, Offset: i}
+		}
+	}
+
+	id, err := parseInt(string(b[start:end]), 10)
+	if err != nil {
+		return 0, err
+	}
+	return ID(id), nil
+}
@@ -0,0 +1,299 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultClockSkew Layout.Validate 默认允许的时钟偏差
+const DefaultClockSkew = time.Second
+
+var (
+	// ErrNegativeID is reported by Layout.Validate for negative IDs
+	ErrNegativeID = errors.New("negative id")
+	// ErrBeforeStart is returned by Layout.Compose and Convert when the time is before the layout start time
+	ErrBeforeStart = errors.New("time before start time")
+	// ErrFutureTime is reported by Layout.Validate when the ID time is in the future beyond the clock skew
+	ErrFutureTime = errors.New("time in the future")
+	// ErrAfterLifetime is reported by Layout.Validate for IDs whose time overflowed past the layout lifetime into the sign bit,
+	// and returned by Layout.Compose and Convert when the time is after the layout lifetime
+	ErrAfterLifetime = errors.New("time after lifetime")
+	// ErrNodeNotAllowed is reported by Layout.Validate when the ID node is not in the allowed set
+	ErrNodeNotAllowed = errors.New("node not allowed")
+	// ErrNodeRange is returned by Layout.Compose when the node does not fit the node bits
+	ErrNodeRange = errors.New("node out of range")
+	// ErrSeqRange is returned by Layout.Compose when the sequence does not fit the sequence bits
+	ErrSeqRange = errors.New("sequence out of range")
+)
+
+// Layout ID 布局, 包括开始时间及节点, 序列位数
+// 用于在没有生成器实例时解析, 校验及构造 ID
+type Layout struct {
+	startTime int64
+	nodeBits  uint8
+	seqBits   uint8
+}
+
+// NewLayout 使用与 New 相同的配置项创建 ID 布局, 忽略节点相关配置
+func NewLayout(opts ...Option) (Layout, error) {
+	options := defaultOptions()
+	for _, o := range opts {
+		o(&options)
+	}
+	sf := Snowflake{opts: options}
+	sf.initBits()
+	if sf.NotTimeBits() > MaxNotTimeBits {
+		return Layout{}, fmt.Errorf("Sum(%d) of node bits and sequence bits must be less than %d", sf.NotTimeBits(), MaxNotTimeBits)
+	}
+	sf.initStartTime()
+	return sf.Layout(), nil
+}
+
+// Layout 返回生成器使用的 ID 布局
+func (sf *Snowflake) Layout() Layout {
+	return Layout{startTime: sf.opts.startTime, nodeBits: sf.opts.nodeBits, seqBits: sf.opts.seqBits}
+}
+
+// StartTime 获取开始时间
+func (l Layout) StartTime() int64 {
+	return l.startTime
+}
+
+// NodeBits 获取节点位数
+func (l Layout) NodeBits() uint8 {
+	return l.nodeBits
+}
+
+// SeqBits 获取序列位数
+func (l Layout) SeqBits() uint8 {
+	return l.seqBits
+}
+
+// TimeBits 获取时间位数
+func (l Layout) TimeBits() uint8 {
+	return MaxBits - l.nodeBits - l.seqBits - 1
+}
+
+// MaxTime 返回可表示的最大消逝时间
+func (l Layout) MaxTime() int64 {
+	return -1 ^ (-1 << l.TimeBits())
+}
+
+// MaxNode 返回最大节点值
+func (l Layout) MaxNode() int64 {
+	return -1 ^ (-1 << l.nodeBits)
+}
+
+// MaxSeq 返回最大序列值
+func (l Layout) MaxSeq() int64 {
+	return -1 ^ (-1 << l.seqBits)
+}
+
+// Lifetime 返回可生成 ID 的最后时间
+func (l Layout) Lifetime() time.Time {
+	return toTime(l.MaxTime() + l.startTime)
+}
+
+// Time 获取 ID 表示的时间整型值
+func (l Layout) Time(id ID) int64 {
+	return (int64(id) >> (l.nodeBits + l.seqBits)) + l.startTime
+}
+
+// StdTime 获取 ID 表示的标准时间类型值
+func (l Layout) StdTime(id ID) time.Time {
+	return toTime(l.Time(id))
+}
+
+// Node 获取 ID 表示的节点值
+func (l Layout) Node(id ID) int64 {
+	return int64(id) >> l.seqBits & l.MaxNode()
+}
+
+// Seq 获取 ID 表示的序列值
+func (l Layout) Seq(id ID) int64 {
+	return int64(id) & l.MaxSeq()
+}
+
+// Compose 使用时间 (毫秒精度), 节点及序列值构造 ID, 是 Time, Node, Seq 的逆运算
+// 时间需在 [StartTime, Lifetime] 内, 零值时间视为早于开始时间, 节点及序列值不能超过 MaxNode 及 MaxSeq
+func (l Layout) Compose(t time.Time, node, seq int64) (ID, error) {
+	e := t.UnixMilli() - l.startTime
+	if e < 0 {
+		return -1, fmt.Errorf("%w: %v before %v", ErrBeforeStart, t, toTime(l.startTime))
+	}
+	if e > l.MaxTime() {
+		return -1, fmt.Errorf("%w: %v after %v", ErrAfterLifetime, t, l.Lifetime())
+	}
+	if node < 0 || node > l.MaxNode() {
+		return -1, fmt.Errorf("%w: node %d not in [0, %d]", ErrNodeRange, node, l.MaxNode())
+	}
+	if seq < 0 || seq > l.MaxSeq() {
+		return -1, fmt.Errorf("%w: sequence %d not in [0, %d]", ErrSeqRange, seq, l.MaxSeq())
+	}
+	return ID(e<<(l.nodeBits+l.seqBits) | node<<l.seqBits | seq), nil
+}
+
+// Decoded 解码后的 ID 及其编码格式, 时间, 节点及序列值
+type Decoded struct {
+	ID     ID        `json:"id"`
+	Format Format    `json:"format"`
+	Time   time.Time `json:"time"` // UTC 时间
+	Node   int64     `json:"node"`
+	Seq    int64     `json:"seq"`
+}
+
+// Decode 使用 ParseAny 转化任意格式的字符串 s 并解码 ID 的时间, 节点及序列值
+// formats 为空时接受所有格式 Formats()
+func (l Layout) Decode(s string, formats ...Format) (Decoded, error) {
+	if len(formats) == 0 {
+		formats = Formats()
+	}
+	id, f, err := ParseAny(s, formats...)
+	if err != nil {
+		return Decoded{}, err
+	}
+	t, node, seq := l.Decompose(id)
+	return Decoded{ID: id, Format: f, Time: t.UTC(), Node: node, Seq: seq}, nil
+}
+
+// Decompose 返回 ID 表示的时间, 节点及序列值, 是 Compose 的逆运算
+func (l Layout) Decompose(id ID) (t time.Time, node, seq int64) {
+	return l.StdTime(id), l.Node(id), l.Seq(id)
+}
+
+// Convert 转化 from 布局的 ID 到 to 布局, 保持时间, 节点及序列值不变
+// 转化保持 ID 顺序; 时间, 节点或序列值超出目标布局范围时返回错误
+func Convert(id ID, from, to Layout) (ID, error) {
+	if id < 0 {
+		return -1, ErrNegativeID
+	}
+	t, node, seq := from.Decompose(id)
+	return to.Compose(t, node, seq)
+}
+
+// MinIDForTime 返回时间 t (毫秒精度) 可产生的最小 ID
+// t 早于开始时间时返回 0, 晚于生命周期时使用最大时间
+func (l Layout) MinIDForTime(t time.Time) ID {
+	return ID(l.elapsed(t) << (l.nodeBits + l.seqBits))
+}
+
+// MaxIDForTime 返回时间 t (毫秒精度) 可产生的最大 ID
+// t 早于开始时间时没有可产生的 ID, 返回 -1, 晚于生命周期时使用最大时间
+func (l Layout) MaxIDForTime(t time.Time) ID {
+	if t.UnixMilli() < l.startTime {
+		return -1
+	}
+	shift := l.nodeBits + l.seqBits
+	return ID(l.elapsed(t)<<shift | (1<<shift - 1))
+}
+
+// Range 返回时间区间 [from, to] 内产生的 ID 范围 [min, max], 可用于数据库范围查询
+// 区间内没有可产生的 ID (to 早于开始时间或早于 from) 时 min > max, BETWEEN 查询结果为空:
+//
+//	min, max := layout.Range(t1, t2)
+//	db.Query("SELECT * FROM orders WHERE id BETWEEN $1 AND $2", min, max)
+func (l Layout) Range(from, to time.Time) (min, max ID) {
+	return l.MinIDForTime(from), l.MaxIDForTime(to)
+}
+
+// elapsed 返回 t 距开始时间的毫秒数, 限定在 [0, MaxTime]
+// 与 epoch 不同, 零值时间不替换为默认开始时间
+func (l Layout) elapsed(t time.Time) int64 {
+	e := t.UnixMilli() - l.startTime
+	if e < 0 {
+		return 0
+	}
+	if max := l.MaxTime(); e > max {
+		return max
+	}
+	return e
+}
+
+// ValidateOption 校验配置项
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	skew  time.Duration
+	nodes map[int64]bool
+	now   time.Time
+}
+
+// ClockSkew 设置允许 ID 时间超前当前时间的偏差, 默认 DefaultClockSkew
+func ClockSkew(skew time.Duration) ValidateOption {
+	return func(o *validateOptions) {
+		o.skew = skew
+	}
+}
+
+// AllowNodes 设置允许的节点值, 未设置时允许所有节点
+func AllowNodes(nodes ...int64) ValidateOption {
+	return func(o *validateOptions) {
+		if o.nodes == nil {
+			o.nodes = make(map[int64]bool, len(nodes))
+		}
+		for _, n := range nodes {
+			o.nodes[n] = true
+		}
+	}
+}
+
+// ValidateAt 设置校验时使用的当前时间, 默认 time.Now()
+func ValidateAt(now time.Time) ValidateOption {
+	return func(o *validateOptions) {
+		o.now = now
+	}
+}
+
+// ValidationError 校验 ID 发现的所有问题
+type ValidationError struct {
+	ID       ID
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Error()
+	}
+	return fmt.Sprintf("invalid snowflake ID %d: %s", e.ID, strings.Join(msgs, ", "))
+}
+
+// Is 任一问题匹配 target 时返回 true, 支持 errors.Is(err, ErrFutureTime) 等判断
+func (e *ValidationError) Is(target error) bool {
+	for _, p := range e.Problems {
+		if errors.Is(p, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 校验 ID 是否可能由该布局的生成器产生, 有问题时返回 *ValidationError
+// 检查负数, 时间晚于生命周期, 时间超前当前时间超过允许偏差及节点是否允许
+// 非负 ID 的时间段不会早于开始时间, 也不会超过 MaxTime;
+// 生成器时钟超过 Lifetime 后时间段溢出到符号位, 产生负数 ID, 因此负数 ID 同时报告 ErrAfterLifetime
+func (l Layout) Validate(id ID, opts ...ValidateOption) error {
+	options := validateOptions{skew: DefaultClockSkew}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.now.IsZero() {
+		options.now = time.Now()
+	}
+
+	var problems []error
+	if id < 0 {
+		problems = append(problems, ErrNegativeID, ErrAfterLifetime)
+	} else if l.Time(id) > epoch(options.now.Add(options.skew)) {
+		problems = append(problems, ErrFutureTime)
+	}
+	if options.nodes != nil && !options.nodes[l.Node(id)] {
+		problems = append(problems, ErrNodeNotAllowed)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{ID: id, Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLayout(t *testing.T) {
+	opts := []Option{Node(12), StartTime(1014729292099), NodeBits(5), SeqBits(12)}
+	sf := MustNew(opts...)
+	l, err := NewLayout(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != sf.Layout() {
+		t.Fatalf("NewLayout %+v != Snowflake.Layout %+v", l, sf.Layout())
+	}
+	if l.MaxTime() != sf.MaxTime() || l.MaxNode() != sf.MaxNode() || l.MaxSeq() != sf.MaxSeq() || !l.Lifetime().Equal(sf.Lifetime()) {
+		t.Fatalf("Layout limits differ from Snowflake")
+	}
+
+	id := sf.ID()
+	if l.Time(id) != id.Time(opts...) || l.Node(id) != id.Node(opts...) || l.Seq(id) != id.Seq(opts...) {
+		t.Fatalf("Layout parts [%d|%d|%d] differ from ID parts [%d|%d|%d]",
+			l.Time(id), l.Node(id), l.Seq(id), id.Time(opts...), id.Node(opts...), id.Seq(opts...))
+	}
+	if !l.StdTime(id).Equal(id.StdTime(opts...)) {
+		t.Fatalf("StdTime %v != %v", l.StdTime(id), id.StdTime(opts...))
+	}
+
+	if _, err := NewLayout(NodeBits(12), SeqBits(12)); err == nil {
+		t.Fatal("no error with 24 node and sequence bits")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	sf := MustNew(Node(5))
+	l := sf.Layout()
+	id := sf.ID()
+	if err := l.Validate(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Validate(id, AllowNodes(4, 5)); err != nil {
+		t.Fatal(err)
+	}
+
+	future := ID((Epoch(time.Now().Add(time.Hour)) - l.StartTime()) << (l.NodeBits() + l.SeqBits()))
+	// 生成器时钟超过 Lifetime 1 毫秒时产生的 ID, 时间段溢出到符号位
+	overflowed := ID((l.MaxTime()+1)<<(l.NodeBits()+l.SeqBits()) | 5<<l.SeqBits())
+	tt := []struct {
+		name     string
+		id       ID
+		opts     []ValidateOption
+		expected []error
+	}{
+		{"negative", -1, nil, []error{ErrNegativeID, ErrAfterLifetime}},
+		{"after lifetime", overflowed, []ValidateOption{ValidateAt(l.Lifetime().Add(time.Hour))}, []error{ErrNegativeID, ErrAfterLifetime}},
+		{"future", future, nil, []error{ErrFutureTime}},
+		{"node", id, []ValidateOption{AllowNodes(1, 2)}, []error{ErrNodeNotAllowed}},
+		{"past", id, []ValidateOption{ValidateAt(time.Now().Add(-time.Hour))}, []error{ErrFutureTime}},
+	}
+	for _, tc := range tt {
+		err := l.Validate(tc.id, tc.opts...)
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Fatalf("%s: expected ValidationError, got %v", tc.name, err)
+		}
+		if len(ve.Problems) != len(tc.expected) {
+			t.Fatalf("%s: expected problems %v, got %v", tc.name, tc.expected, ve.Problems)
+		}
+		for _, e := range tc.expected {
+			if !errors.Is(err, e) {
+				t.Fatalf("%s: expected %v in %v", tc.name, e, err)
+			}
+		}
+	}
+
+	if err := l.Validate(future, ClockSkew(2*time.Hour)); err != nil {
+		t.Fatalf("future id within clock skew: %v", err)
+	}
+}
+
+func TestIDForTime(t *testing.T) {
+	for _, opts := range [][]Option{{Node(1)}, {Node(3), NodeBits(5), SeqBits(12), StartTime(1014729292099)}} {
+		sf := MustNew(opts...)
+		l := sf.Layout()
+
+		from := time.Now()
+		ids := sf.Batch(3000)
+		to := time.Now()
+
+		min, max := l.Range(from, to)
+		if min > max {
+			t.Fatalf("min %d > max %d", min, max)
+		}
+		for _, id := range ids {
+			if id < min || id > max {
+				t.Fatalf("id %d out of range [%d, %d]", id, min, max)
+			}
+			ts := l.StdTime(id)
+			if id < l.MinIDForTime(ts) || id > l.MaxIDForTime(ts) {
+				t.Fatalf("id %d out of its own time bounds", id)
+			}
+		}
+
+		min = l.MinIDForTime(from)
+		if l.Time(min) != Epoch(from) || l.Node(min) != 0 || l.Seq(min) != 0 {
+			t.Fatalf("MinIDForTime parts [%d|%d|%d]", l.Time(min), l.Node(min), l.Seq(min))
+		}
+		max = l.MaxIDForTime(from)
+		if l.Time(max) != Epoch(from) || l.Node(max) != l.MaxNode() || l.Seq(max) != l.MaxSeq() {
+			t.Fatalf("MaxIDForTime parts [%d|%d|%d]", l.Time(max), l.Node(max), l.Seq(max))
+		}
+		if next := l.MinIDForTime(from.Add(time.Millisecond)); next != max+1 {
+			t.Fatalf("MinIDForTime of next millisecond %d != %d", next, max+1)
+		}
+
+		// 超出范围
+		if id := l.MinIDForTime(toTime(l.StartTime() - 1000)); id != 0 {
+			t.Fatalf("MinIDForTime before start = %d, expected 0", id)
+		}
+		if id := l.MinIDForTime(time.Time{}); id != 0 {
+			t.Fatalf("MinIDForTime of zero time = %d, expected 0", id)
+		}
+		before := toTime(l.StartTime() - 1)
+		if id := l.MaxIDForTime(before); id != -1 {
+			t.Fatalf("MaxIDForTime before start = %d, expected -1", id)
+		}
+		if min, max := l.Range(before.Add(-time.Hour), before); min <= max {
+			t.Fatalf("Range before start = [%d, %d], expected empty", min, max)
+		}
+		if min, max := l.Range(time.Time{}, toTime(l.StartTime())); min != 0 || max != l.MaxIDForTime(toTime(l.StartTime())) {
+			t.Fatalf("Range to start = [%d, %d]", min, max)
+		}
+		if id := l.MaxIDForTime(l.Lifetime().Add(time.Hour)); id != math.MaxInt64 {
+			t.Fatalf("MaxIDForTime after lifetime = %d, expected MaxInt64", id)
+		}
+	}
+}
+
+func TestCompose(t *testing.T) {
+	opts := []Option{Node(12), NodeBits(5), SeqBits(12), StartTime(1014729292099)}
+	sf := MustNew(opts...)
+	l := sf.Layout()
+
+	for _, id := range sf.Batch(5000) {
+		ts, node, seq := l.Decompose(id)
+		cID, err := l.Compose(ts, node, seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cID != id {
+			t.Fatalf("Compose(Decompose(%d)) = %d", id, cID)
+		}
+		if node != 12 || node != id.Node(opts...) || seq != id.Seq(opts...) || Epoch(ts) != id.Time(opts...) {
+			t.Fatalf("Decompose(%d) = %v, %d, %d", id, ts, node, seq)
+		}
+	}
+
+	now := time.Now()
+	id, err := l.Compose(now, l.MaxNode(), l.MaxSeq())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != l.MaxIDForTime(now) {
+		t.Fatalf("Compose max parts %d != MaxIDForTime %d", id, l.MaxIDForTime(now))
+	}
+
+	tt := []struct {
+		name      string
+		t         time.Time
+		node, seq int64
+		expected  error
+	}{
+		{"before start", toTime(l.StartTime() - 1), 0, 0, ErrBeforeStart},
+		{"zero time", time.Time{}, 0, 0, ErrBeforeStart},
+		{"after lifetime", l.Lifetime().Add(time.Millisecond), 0, 0, ErrAfterLifetime},
+		{"node", now, l.MaxNode() + 1, 0, ErrNodeRange},
+		{"negative node", now, -1, 0, ErrNodeRange},
+		{"seq", now, 0, l.MaxSeq() + 1, ErrSeqRange},
+		{"negative seq", now, 0, -1, ErrSeqRange},
+	}
+	for _, tc := range tt {
+		if _, err := l.Compose(tc.t, tc.node, tc.seq); !errors.Is(err, tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, err)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	sf := MustNew(Node(300))
+	from := sf.Layout()
+	to, _ := NewLayout(NodeBits(16), SeqBits(6), StartTime(Epoch(time.Now().Add(-time.Hour))))
+
+	// 序列值超出 6 位
+	ids := sf.Batch(200)
+	var last ID
+	var fit int
+	for _, id := range ids {
+		cID, err := Convert(id, from, to)
+		if errors.Is(err, ErrSeqRange) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		fit++
+		if cID <= last {
+			t.Fatalf("converted id %d is not greater than %d", cID, last)
+		}
+		last = cID
+		if to.Node(cID) != 300 || to.Seq(cID) != from.Seq(id) || to.Time(cID) != from.Time(id) {
+			t.Fatalf("Convert(%d) parts differ", id)
+		}
+		back, err := Convert(cID, to, from)
+		if err != nil || back != id {
+			t.Fatalf("Convert back %d = %d, %v", cID, back, err)
+		}
+	}
+	if fit == 0 {
+		t.Fatal("no id converted")
+	}
+
+	// 节点值超出目标位数
+	small, _ := NewLayout(NodeBits(8), SeqBits(12))
+	if _, err := Convert(ids[0], from, small); !errors.Is(err, ErrNodeRange) {
+		t.Fatalf("Expected ErrNodeRange, got %v", err)
+	}
+	// 时间早于目标开始时间
+	late, _ := NewLayout(StartTime(Epoch(time.Now().Add(time.Hour))))
+	if _, err := Convert(ids[0], from, late); !errors.Is(err, ErrBeforeStart) {
+		t.Fatalf("Expected ErrBeforeStart, got %v", err)
+	}
+	if _, err := Convert(-1, from, to); err != ErrNegativeID {
+		t.Fatalf("Expected ErrNegativeID, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	l, _ := NewLayout(NodeBits(16), SeqBits(6))
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)
+	id, _ := l.Compose(ts, 7, 9)
+
+	d, err := l.Decode(id.Tagged(FormatHex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != id || d.Format != FormatHex || !d.Time.Equal(ts) || d.Time.Location() != time.UTC || d.Node != 7 || d.Seq != 9 {
+		t.Fatalf("Decode = %+v", d)
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":"` + id.String() + `","format":"hex","time":"2024-01-02T03:04:05.006Z","node":7,"seq":9}`
+	if string(b) != expected {
+		t.Fatalf("json = %s, expected %s", b, expected)
+	}
+
+	if d, err := l.Decode(id.Base62(), FormatBase62); err != nil || d.ID != id || d.Format != FormatBase62 {
+		t.Fatalf("Decode base62 = %+v, %v", d, err)
+	}
+	if _, err := l.Decode(id.Tagged(FormatHex), FormatBase62); err != ErrFormatNotAllowed {
+		t.Fatalf("Expected ErrFormatNotAllowed, got %v", err)
+	}
+	if _, err := l.Decode("!!"); err != ErrNoFormat {
+		t.Fatalf("Expected ErrNoFormat, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package netid
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+// Client ID 客户端, 可并发使用
+// 客户端最多保持 PoolSize 个连接, 每个请求独占一个连接
+type Client struct {
+	network string
+	addr    string
+	opts    Options
+	dialer  net.Dialer
+	sem     chan struct{} // 限制连接数
+
+	mu     sync.Mutex
+	idle   []*clientConn
+	closed bool
+}
+
+// clientConn 客户端连接
+type clientConn struct {
+	net.Conn
+	r   *bufio.Reader
+	buf []byte
+}
+
+// Dial 创建连接 network 网络 addr 地址的客户端, network 为 tcp 或 unix 等
+// 创建时建立一个连接以检查服务端是否可用
+func Dial(network, addr string, opts ...Option) (*Client, error) {
+	options := newOptions(opts)
+	c := &Client{
+		network: network,
+		addr:    addr,
+		opts:    options,
+		dialer:  net.Dialer{Timeout: options.timeout},
+		sem:     make(chan struct{}, options.poolSize),
+	}
+	cc, err := c.dial(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	c.idle = append(c.idle, cc)
+	return c, nil
+}
+
+// ID 获取一个 ID
+func (c *Client) ID(ctx context.Context) (snowflake.ID, error) {
+	var id [1]snowflake.ID
+	if err := c.fill(ctx, id[:]); err != nil {
+		return -1, err
+	}
+	return id[0], nil
+}
+
+// IDs 获取 n 个 ID, 超过 MaxBatch 时拆分为多个请求, ID 按获取顺序排列
+func (c *Client) IDs(ctx context.Context, n int) ([]snowflake.ID, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCount, n)
+	}
+	ids := make([]snowflake.ID, n)
+	for i := 0; i < n; i += c.opts.maxBatch {
+		end := i + c.opts.maxBatch
+		if end > n {
+			end = n
+		}
+		if err := c.fill(ctx, ids[i:end]); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// Close 关闭客户端及空闲连接, 使用中的连接在请求结束后关闭
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	var err error
+	for _, cc := range c.idle {
+		if cerr := cc.Close(); err == nil {
+			err = cerr
+		}
+	}
+	c.idle = nil
+	return err
+}
+
+// fill 通过一个请求获取 ID 填充 ids
+func (c *Client) fill(ctx context.Context, ids []snowflake.ID) error {
+	for {
+		cc, reused, err := c.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		ok, err := cc.request(ctx, c.opts.timeout, ids)
+		c.release(cc, ok)
+		if err == nil || ok || !reused || ctx.Err() != nil || isTimeout(err) {
+			return err
+		}
+		// 空闲连接可能已被服务端因空闲超时关闭, 使用其他连接重试
+	}
+}
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// acquire 获取空闲连接, 没有空闲连接且未达到连接数上限时建立新连接, reused 表示是否为空闲连接
+func (c *Client) acquire(ctx context.Context) (cc *clientConn, reused bool, err error) {
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		<-c.sem
+		return nil, false, ErrClientClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cc := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cc, true, nil
+	}
+	c.mu.Unlock()
+
+	cc, err = c.dial(ctx)
+	if err != nil {
+		<-c.sem
+		return nil, false, err
+	}
+	return cc, false, nil
+}
+
+// release 归还连接, ok 为 false 或客户端已关闭时关闭连接
+func (c *Client) release(cc *clientConn, ok bool) {
+	c.mu.Lock()
+	if ok && !c.closed {
+		c.idle = append(c.idle, cc)
+	} else {
+		cc.Close()
+	}
+	c.mu.Unlock()
+	<-c.sem
+}
+
+func (c *Client) dial(ctx context.Context) (*clientConn, error) {
+	conn, err := c.dialer.DialContext(ctx, c.network, c.addr)
+	if err != nil {
+		return nil, err
+	}
+	return &clientConn{Conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+// request 发送请求并读取响应填充 ids, 返回连接是否可继续使用
+// ctx 取消时中断读写并返回 ctx.Err()
+func (cc *clientConn) request(ctx context.Context, timeout time.Duration, ids []snowflake.ID) (ok bool, err error) {
+	if done := ctx.Done(); done != nil {
+		// ctx 取消时设置已过期的截止时间, 使阻塞的读写立即返回
+		stop, exited := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(exited)
+			select {
+			case <-done:
+				cc.SetDeadline(time.Unix(1, 0))
+			case <-stop:
+			}
+		}()
+		defer func() {
+			close(stop)
+			<-exited
+			if err != nil && ctx.Err() != nil {
+				ok, err = false, ctx.Err()
+			}
+		}()
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	if err := cc.SetDeadline(deadline); err != nil {
+		return false, err
+	}
+
+	var req [headerLen + requestLen]byte
+	binary.BigEndian.PutUint32(req[:], requestLen)
+	binary.BigEndian.PutUint32(req[headerLen:], uint32(len(ids)))
+	if _, err := cc.Write(req[:]); err != nil {
+		return false, err
+	}
+
+	max := 1 + 8*len(ids)
+	if max < 1+maxErrorLen {
+		max = 1 + maxErrorLen
+	}
+	payload, err := readFrame(cc.r, cc.buf, max)
+	if err != nil {
+		return false, err
+	}
+	cc.buf = payload[:0]
+	if len(payload) == 0 {
+		return false, fmt.Errorf("netid: empty response")
+	}
+	switch payload[0] {
+	case StatusOK:
+		if len(payload) != 1+8*len(ids) {
+			return false, fmt.Errorf("netid: got %d bytes of ids, want %d", len(payload)-1, 8*len(ids))
+		}
+		for i := range ids {
+			ids[i] = snowflake.ID(binary.BigEndian.Uint64(payload[1+8*i:]))
+		}
+		return true, nil
+	}
+	if err, ok := statusErrors[payload[0]]; ok {
+		// 帧过大时服务端关闭连接
+		return payload[0] != StatusFrameTooLarge, &serverError{err: err, msg: string(payload[1:])}
+	}
+	return false, fmt.Errorf("netid: unknown response status %d", payload[0])
+}
+
+// serverError 服务端错误响应, 错误信息为服务端返回的内容, 可使用 errors.Is 匹配对应的错误变量
+type serverError struct {
+	err error
+	msg string
+}
+
+func (e *serverError) Error() string { return e.msg }
+
+func (e *serverError) Unwrap() error { return e.err }
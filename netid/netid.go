@@ -0,0 +1,161 @@
+// Package netid 通过 TCP 或 Unix 域套接字提供 Snowflake ID 服务
+//
+// 协议使用长度前缀帧, 长度为 4 字节大端无符号整数, 不包含自身:
+//
+//	请求: | 长度 = 4 | 4 字节 ID 数量 n |
+//	响应: | 长度 | 1 字节状态 | 内容 |
+//
+// 状态为 StatusOK 时内容为 n 个 8 字节大端 int64 ID, 按生成顺序排列;
+// 其他状态为错误, 内容为 UTF-8 错误信息, 客户端将状态转化为对应的错误变量.
+// StatusFrameTooLarge 响应后服务端关闭连接, 其他错误响应后连接仍可继续使用.
+// 一个连接上的请求按顺序处理, 客户端使用连接池实现并发.
+package netid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+const (
+	DefaultMaxBatch           = 4096            // 单次请求默认最多生成的 ID 数量
+	DefaultPoolSize           = 4               // 客户端默认最大连接数
+	DefaultTimeout            = 5 * time.Second // 客户端默认请求超时时间
+	DefaultIdleTimeout        = 2 * time.Minute // 服务端默认连接空闲超时时间
+	StatusOK             byte = 0               // 响应状态 成功
+	StatusError          byte = 1               // 响应状态 其他错误
+	StatusInvalidCount   byte = 2               // 响应状态 ID 数量超出范围, 对应 ErrInvalidCount
+	StatusInvalidRequest byte = 3               // 响应状态 请求格式错误, 对应 ErrInvalidRequest
+	StatusFrameTooLarge  byte = 4               // 响应状态 请求帧过大, 对应 ErrFrameTooLarge
+	maxErrorLen               = 1024            // 错误信息最大长度
+	requestLen                = 4               // 请求帧内容长度
+	headerLen                 = 4               // 帧长度前缀
+)
+
+var (
+	// ErrServerClosed is returned by Server.Serve after Server.Close
+	ErrServerClosed = errors.New("netid: server closed")
+	// ErrClientClosed is returned by Client methods after Client.Close
+	ErrClientClosed = errors.New("netid: client closed")
+	// ErrFrameTooLarge is returned when a frame length exceeds the allowed size
+	ErrFrameTooLarge = errors.New("netid: frame too large")
+	// ErrInvalidCount is returned when the requested number of IDs is out of range
+	ErrInvalidCount = errors.New("netid: invalid id count")
+	// ErrInvalidRequest is returned by Client when the server rejects a malformed request
+	ErrInvalidRequest = errors.New("netid: invalid request")
+	// ErrServer is returned by Client when the server reports an error without a more specific status
+	ErrServer = errors.New("netid: server error")
+)
+
+// statusErrors 错误响应状态对应的错误变量
+var statusErrors = map[byte]error{
+	StatusError:          ErrServer,
+	StatusInvalidCount:   ErrInvalidCount,
+	StatusInvalidRequest: ErrInvalidRequest,
+	StatusFrameTooLarge:  ErrFrameTooLarge,
+}
+
+// Options 配置项
+type Options struct {
+	maxBatch int           // 单次请求最多生成的 ID 数量, 服务端拒绝超出的请求, 客户端据此拆分请求
+	poolSize int           // 客户端最大连接数
+	timeout  time.Duration // 客户端请求超时时间
+	idle     time.Duration // 服务端连接空闲超时时间
+}
+
+type Option func(*Options)
+
+// MaxBatch 设置单次请求最多生成的 ID 数量
+func MaxBatch(n int) Option {
+	return func(o *Options) {
+		o.maxBatch = n
+	}
+}
+
+// PoolSize 设置客户端最大连接数
+func PoolSize(n int) Option {
+	return func(o *Options) {
+		o.poolSize = n
+	}
+}
+
+// Timeout 设置客户端请求超时时间, context 的截止时间更早时使用后者, 为 0 时不设置超时
+func Timeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.timeout = d
+	}
+}
+
+// IdleTimeout 设置服务端连接空闲超时时间, 超时未收到完整请求或无法写出响应时关闭连接, 为 0 时不设置超时
+func IdleTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.idle = d
+	}
+}
+
+func newOptions(opts []Option) Options {
+	options := Options{maxBatch: DefaultMaxBatch, poolSize: DefaultPoolSize, timeout: DefaultTimeout, idle: DefaultIdleTimeout}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.maxBatch <= 0 {
+		options.maxBatch = DefaultMaxBatch
+	}
+	if options.poolSize <= 0 {
+		options.poolSize = DefaultPoolSize
+	}
+	return options
+}
+
+// readFrame 读取长度不超过 max 的帧内容, buf 容量足够时复用
+func readFrame(r io.Reader, buf []byte, max int) ([]byte, error) {
+	var hdr [headerLen]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if uint64(n) > uint64(max) {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, n)
+	}
+	if cap(buf) < int(n) {
+		buf = make([]byte, n)
+	}
+	buf = buf[:n]
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
+
+// appendResponse 追加成功响应帧
+func appendResponse(b []byte, ids []snowflake.ID) []byte {
+	b = appendUint32(b, uint32(1+8*len(ids)))
+	b = append(b, StatusOK)
+	for _, id := range ids {
+		b = append(b, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(b[len(b)-8:], uint64(id))
+	}
+	return b
+}
+
+// appendError 追加状态为 status 的错误响应帧
+func appendError(b []byte, status byte, err error) []byte {
+	msg := err.Error()
+	if len(msg) > maxErrorLen {
+		msg = msg[:maxErrorLen]
+	}
+	b = appendUint32(b, uint32(1+len(msg)))
+	b = append(b, status)
+	return append(b, msg...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
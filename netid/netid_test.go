@@ -0,0 +1,335 @@
+package netid
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+func startServer(t *testing.T, network, addr string, opts ...Option) (*Server, net.Listener) {
+	t.Helper()
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(snowflake.MustNew(snowflake.Node(1)), opts...)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ln)
+	}()
+	t.Cleanup(func() {
+		srv.Close()
+		if err := <-done; err != ErrServerClosed {
+			t.Errorf("Serve returned %v, want ErrServerClosed", err)
+		}
+	})
+	return srv, ln
+}
+
+func dial(t *testing.T, ln net.Listener, opts ...Option) *Client {
+	t.Helper()
+	c, err := Dial(ln.Addr().Network(), ln.Addr().String(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func checkIncreasing(t *testing.T, ids []snowflake.ID) {
+	t.Helper()
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("id %d is not greater than %d", ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestTCP(t *testing.T) {
+	_, ln := startServer(t, "tcp", "127.0.0.1:0")
+	c := dial(t, ln)
+	ctx := context.Background()
+
+	id, err := c.ID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node := id.Node(); node != 1 {
+		t.Fatalf("node = %d, want 1", node)
+	}
+	ids, err := c.IDs(ctx, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1000 {
+		t.Fatalf("got %d ids, want 1000", len(ids))
+	}
+	checkIncreasing(t, append([]snowflake.ID{id}, ids...))
+
+	if _, err := c.IDs(ctx, 0); !errors.Is(err, ErrInvalidCount) {
+		t.Fatalf("IDs(0) error = %v, want ErrInvalidCount", err)
+	}
+}
+
+func TestUnix(t *testing.T) {
+	_, ln := startServer(t, "unix", filepath.Join(t.TempDir(), "netid.sock"))
+	c := dial(t, ln)
+
+	ids, err := c.IDs(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkIncreasing(t, ids)
+}
+
+func TestSplitBatch(t *testing.T) {
+	_, ln := startServer(t, "tcp", "127.0.0.1:0", MaxBatch(100))
+	c := dial(t, ln, MaxBatch(100))
+
+	ids, err := c.IDs(context.Background(), 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 250 {
+		t.Fatalf("got %d ids, want 250", len(ids))
+	}
+	checkIncreasing(t, ids)
+
+	// 客户端拆分大小大于服务端限制时返回服务端错误, 连接仍可使用
+	big := dial(t, ln, MaxBatch(200), PoolSize(1))
+	if _, err := big.IDs(context.Background(), 150); !errors.Is(err, ErrInvalidCount) {
+		t.Fatalf("error = %v, want ErrInvalidCount", err)
+	}
+	if _, err := big.ID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPool(t *testing.T) {
+	srv, ln := startServer(t, "tcp", "127.0.0.1:0")
+	c := dial(t, ln, PoolSize(3))
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[snowflake.ID]bool)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				ids, err := c.IDs(context.Background(), 20)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				for _, id := range ids {
+					if seen[id] {
+						t.Errorf("duplicate id %d", id)
+					}
+					seen[id] = true
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 16*50*20 {
+		t.Fatalf("got %d ids, want %d", len(seen), 16*50*20)
+	}
+
+	srv.mu.Lock()
+	conns := len(srv.conns)
+	srv.mu.Unlock()
+	if conns > 3 {
+		t.Fatalf("server has %d connections, want at most 3", conns)
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	_, ln := startServer(t, "tcp", "127.0.0.1:0", MaxBatch(10))
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+
+	send := func(frame ...byte) {
+		t.Helper()
+		if _, err := conn.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectStatus := func(status byte) []byte {
+		t.Helper()
+		payload, err := readFrame(r, nil, 1+maxErrorLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if payload[0] != status {
+			t.Fatalf("status = %d, want %d: %q", payload[0], status, payload[1:])
+		}
+		return payload[1:]
+	}
+
+	send(0, 0, 0, 4, 0, 0, 0, 11)
+	expectStatus(StatusInvalidCount)
+	send(0, 0, 0, 2, 0, 1)
+	expectStatus(StatusInvalidRequest)
+	send(0, 0, 0, 4, 0, 0, 0, 2)
+	if body := expectStatus(StatusOK); len(body) != 16 {
+		t.Fatalf("got %d bytes, want 16", len(body))
+	} else if id0, id1 := binary.BigEndian.Uint64(body), binary.BigEndian.Uint64(body[8:]); id1 <= id0 {
+		t.Fatalf("id %d is not greater than %d", id1, id0)
+	}
+
+	// 帧过大时返回错误并关闭连接
+	send(0, 1, 0, 0)
+	if body := expectStatus(StatusFrameTooLarge); !strings.Contains(string(body), "frame too large") {
+		t.Fatalf("error = %q", body)
+	}
+	if _, err := r.ReadByte(); err == nil {
+		t.Fatal("expected closed connection")
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	srv, ln := startServer(t, "tcp", "127.0.0.1:0", IdleTimeout(100*time.Millisecond))
+
+	// 空闲及未发送完整请求的连接超时后被关闭
+	for _, partial := range [][]byte{nil, {0, 0, 0, 4, 0}} {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Write(partial); err != nil {
+			t.Fatal(err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, err := conn.Read(make([]byte, 1)); err == nil {
+			t.Fatal("expected closed connection")
+		} else if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			t.Fatal("server did not close idle connection")
+		}
+		conn.Close()
+	}
+
+	// 客户端空闲连接被服务端关闭后自动使用新连接重试
+	c := dial(t, ln, PoolSize(1))
+	if _, err := c.ID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, err := c.ID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.mu.Lock()
+	conns := len(srv.conns)
+	srv.mu.Unlock()
+	if conns > 1 {
+		t.Fatalf("server has %d connections, want at most 1", conns)
+	}
+}
+
+func TestClose(t *testing.T) {
+	srv, ln := startServer(t, "tcp", "127.0.0.1:0")
+	c := dial(t, ln)
+	if _, err := c.ID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Close()
+	if _, err := c.ID(context.Background()); err != ErrClientClosed {
+		t.Fatalf("error = %v, want ErrClientClosed", err)
+	}
+
+	c2 := dial(t, ln)
+	srv.Close()
+	if _, err := c2.ID(context.Background()); err == nil {
+		t.Fatal("expected error after server close")
+	}
+	if err := srv.Serve(ln); err != ErrServerClosed {
+		t.Fatalf("Serve after Close error = %v, want ErrServerClosed", err)
+	}
+}
+
+func TestContext(t *testing.T) {
+	// 服务端不响应时按 context 截止时间超时
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	c := dial(t, ln)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.ID(ctx)
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		t.Fatalf("error = %v, want timeout", err)
+	}
+
+	cancel()
+	if _, err := c.ID(ctx); err == nil {
+		t.Fatal("expected error for done context")
+	}
+
+	// 没有截止时间的 context 取消时立即返回, 不等待 Timeout
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := c.ID(ctx); err != context.Canceled {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("cancel took %v", d)
+	}
+}
+
+func BenchmarkClientID(b *testing.B) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	srv := NewServer(snowflake.MustNew(snowflake.Node(1)))
+	go srv.Serve(ln)
+	defer srv.Close()
+	c, err := Dial("tcp", ln.Addr().String())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.ID(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
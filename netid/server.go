@@ -0,0 +1,161 @@
+package netid
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+// Server ID 服务端
+type Server struct {
+	sf       *snowflake.Snowflake
+	maxBatch int
+	idle     time.Duration
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer 创建使用 sf 生成 ID 的服务端
+func NewServer(sf *snowflake.Snowflake, opts ...Option) *Server {
+	options := newOptions(opts)
+	return &Server{
+		sf:        sf,
+		maxBatch:  options.maxBatch,
+		idle:      options.idle,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve 在 ln 上接受连接并处理请求, 直到 ln 出错或服务端关闭
+// 服务端关闭后返回 ErrServerClosed
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.listeners[ln] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, ln)
+		s.mu.Unlock()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		if !s.track(conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// ListenAndServe 监听 network 网络的 addr 地址并处理请求, network 为 tcp 或 unix 等
+func (s *Server) ListenAndServe(network, addr string) error {
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Close 关闭所有监听及连接, 等待处理中的请求结束
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	for ln := range s.listeners {
+		if cerr := ln.Close(); err == nil {
+			err = cerr
+		}
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// track 记录连接, 服务端已关闭时返回 false
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+// serveConn 依次处理连接上的请求
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	var req [requestLen]byte
+	var resp []byte
+	for {
+		// 每个请求的读取及响应的写出需在空闲超时内完成
+		if s.idle > 0 {
+			if err := conn.SetDeadline(time.Now().Add(s.idle)); err != nil {
+				return
+			}
+		}
+		payload, err := readFrame(r, req[:], requestLen)
+		if err != nil {
+			// 读取出错或帧过大时无法继续解析后续请求, 关闭连接
+			if errors.Is(err, ErrFrameTooLarge) {
+				conn.Write(appendError(resp[:0], StatusFrameTooLarge, err))
+			}
+			return
+		}
+
+		resp = resp[:0]
+		if len(payload) != requestLen {
+			resp = appendError(resp, StatusInvalidRequest, fmt.Errorf("%w: length %d", ErrInvalidRequest, len(payload)))
+		} else if n := binary.BigEndian.Uint32(payload); n == 0 || n > uint32(s.maxBatch) {
+			resp = appendError(resp, StatusInvalidCount, fmt.Errorf("%w: %d, must be between 1 and %d", ErrInvalidCount, n, s.maxBatch))
+		} else {
+			resp = appendResponse(resp, s.sf.Batch(int(n)))
+		}
+		if _, err := conn.Write(resp); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	obfuscateRounds  = 8  // Feistel 轮数, 必须为偶数
+	obfuscateLBits   = 31 // 左半部分位数
+	obfuscateRBits   = 32 // 右半部分位数
+	MinObfuscatorKey = 16 // 最小密钥长度
+)
+
+// ErrShortKey is returned by NewObfuscator when the key is shorter than MinObfuscatorKey
+var ErrShortKey = errors.New("key too short")
+
+// Obfuscator 基于密钥的可逆 ID 混淆, 隐藏对外 ID 中的时间及数量信息
+// 使用 63 位非平衡 Feistel 网络, 非负 ID 一一映射为非负 ID, 负数 ID 原样返回
+// 混淆用于隐藏信息而非加密, 不能替代访问控制
+type Obfuscator struct {
+	keys [obfuscateRounds]uint64
+}
+
+// NewObfuscator 创建混淆器, 相同密钥产生相同映射
+func NewObfuscator(key []byte) (*Obfuscator, error) {
+	if len(key) < MinObfuscatorKey {
+		return nil, ErrShortKey
+	}
+	var o Obfuscator
+	mac := hmac.New(sha256.New, key)
+	for i := range o.keys {
+		mac.Reset()
+		mac.Write([]byte{'s', 'f', byte(i)})
+		o.keys[i] = binary.BigEndian.Uint64(mac.Sum(nil))
+	}
+	return &o, nil
+}
+
+// Encode 混淆 ID
+func (o *Obfuscator) Encode(id ID) ID {
+	if id < 0 {
+		return id
+	}
+	lBits, rBits := uint(obfuscateLBits), uint(obfuscateRBits)
+	l, r := uint64(id)>>rBits, uint64(id)&mask(rBits)
+	for i := 0; i < obfuscateRounds; i++ {
+		l, r = r, (l^o.round(i, r))&mask(lBits)
+		lBits, rBits = rBits, lBits
+	}
+	return ID(l<<rBits | r)
+}
+
+// Decode 还原混淆后的 ID
+func (o *Obfuscator) Decode(id ID) ID {
+	if id < 0 {
+		return id
+	}
+	lBits, rBits := uint(obfuscateLBits), uint(obfuscateRBits)
+	l, r := uint64(id)>>rBits, uint64(id)&mask(rBits)
+	for i := obfuscateRounds - 1; i >= 0; i-- {
+		l, r = (r^o.round(i, l))&mask(rBits), l
+		lBits, rBits = rBits, lBits
+	}
+	return ID(l<<rBits | r)
+}
+
+// EncodeString 返回混淆后 ID 的 Base58 编码, 其他格式可使用 Format.Encode(o.Encode(id))
+func (o *Obfuscator) EncodeString(id ID) string {
+	return o.Encode(id).Base58()
+}
+
+// DecodeString 转化混淆后 ID 的 Base58 编码到原始 ID
+func (o *Obfuscator) DecodeString(s string) (ID, error) {
+	id, err := ParseBase58String(s)
+	if err != nil {
+		return -1, err
+	}
+	return o.Decode(id), nil
+}
+
+// round Feistel 轮函数
+func (o *Obfuscator) round(i int, v uint64) uint64 {
+	// splitmix64 混合函数
+	z := v ^ o.keys[i]
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func mask(bits uint) uint64 {
+	return 1<<bits - 1
+}
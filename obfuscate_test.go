@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+var obfuscatorKey = []byte("0123456789abcdef")
+
+func TestObfuscator(t *testing.T) {
+	if _, err := NewObfuscator([]byte("short")); err != ErrShortKey {
+		t.Fatalf("Expected ErrShortKey, got %v", err)
+	}
+	o, err := NewObfuscator(obfuscatorKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sf := MustNew(Node(1))
+	ids := append([]ID{0, 1, math.MaxInt64}, sf.Batch(1000)...)
+	seen := make(map[ID]struct{}, len(ids))
+	increasing := 0
+	for i, id := range ids {
+		e := o.Encode(id)
+		if e < 0 {
+			t.Fatalf("Encode(%d) = %d, expected non-negative", id, e)
+		}
+		if d := o.Decode(e); d != id {
+			t.Fatalf("Decode(Encode(%d)) = %d", id, d)
+		}
+		if _, ok := seen[e]; ok {
+			t.Fatalf("Encode(%d) = %d collides", id, e)
+		}
+		seen[e] = struct{}{}
+		if i > 0 && o.Encode(ids[i-1]) < e {
+			increasing++
+		}
+
+		s := o.EncodeString(id)
+		pID, err := o.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pID != id {
+			t.Fatalf("DecodeString(%q) = %d, expected %d", s, pID, id)
+		}
+	}
+
+	// 连续 ID 混淆后不再有序, 相邻递增比例应接近一半
+	if increasing < len(ids)*3/10 || increasing > len(ids)*7/10 {
+		t.Fatalf("%d of %d encoded ids are increasing", increasing, len(ids))
+	}
+	if o.Encode(-5) != -5 || o.Decode(-5) != -5 {
+		t.Fatal("negative ID not returned unchanged")
+	}
+	if _, err := o.DecodeString("0"); err != ErrInvalidBase58 {
+		t.Fatalf("Expected ErrInvalidBase58, got %v", err)
+	}
+
+	// 不同密钥产生不同映射
+	o2, _ := NewObfuscator([]byte("fedcba9876543210"))
+	if o2.Encode(ids[3]) == o.Encode(ids[3]) {
+		t.Fatal("different keys produce the same encoding")
+	}
+}
+
+func TestObfuscatorRoundTrip(t *testing.T) {
+	o, _ := NewObfuscator(obfuscatorKey)
+	prop := func(i int64) bool {
+		id := ID(i & math.MaxInt64)
+		e := o.Encode(id)
+		return e >= 0 && o.Decode(e) == id
+	}
+	if err := quick.Check(prop, &quick.Config{MaxCount: 100000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkObfuscatorEncode(b *testing.B) {
+	o, _ := NewObfuscator(obfuscatorKey)
+	id := MustNew(Node(1)).ID()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		o.Encode(id)
+	}
+}
@@ -0,0 +1,63 @@
+package snowflake
+
+import "time"
+
+// 节点值来源, 见 Observer.OnNodeAcquired
+const (
+	NodeSourceOption  = "option"  // Node 选项
+	NodeSourceEnv     = "env"     // 环境变量 SNOWFLAKE_NODE
+	NodeSourceIP      = "ip"      // 主机私有 IP 地址
+	NodeSourceDefault = "default" // 未找到任何来源, 使用默认值 0
+)
+
+// LifetimeWarning 剩余可用时间小于此值时, 创建实例会触发 Observer.OnLifetimeWarning
+const LifetimeWarning = 365 * 24 * time.Hour
+
+// Observer 生成器生命周期及异常事件观察者
+// 回调均在锁外同步调用, 实现应尽量轻量且并发安全
+type Observer interface {
+	// OnIssued 每产生一个 ID 后调用
+	OnIssued(id ID)
+	// OnSequenceExhausted 当前毫秒序列耗尽, 需要等待下一毫秒时调用, ts 为耗尽时的毫秒时间
+	OnSequenceExhausted(ts int64)
+	// OnClockBackwards 检测到时钟回拨时调用, last 为上次产生 ID 的毫秒时间, now 为当前毫秒时间
+	OnClockBackwards(last, now int64)
+	// OnLifetimeWarning 创建实例时剩余可用时间小于 LifetimeWarning 时调用
+	OnLifetimeWarning(lifetime time.Time, remaining time.Duration)
+	// OnNodeAcquired 创建实例确定节点值后调用, source 为节点值来源
+	OnNodeAcquired(node int64, source string)
+}
+
+// NopObserver 空观察者, 嵌入自定义类型后只需实现关心的回调
+type NopObserver struct{}
+
+func (NopObserver) OnIssued(ID)                                {}
+func (NopObserver) OnSequenceExhausted(int64)                  {}
+func (NopObserver) OnClockBackwards(int64, int64)              {}
+func (NopObserver) OnLifetimeWarning(time.Time, time.Duration) {}
+func (NopObserver) OnNodeAcquired(int64, string)               {}
+
+// Observe 设置事件观察者
+func Observe(observer Observer) Option {
+	return func(o *Options) {
+		o.observer = observer
+	}
+}
+
+// event 产生 ID 时发生的异常事件
+type event struct {
+	exhausted bool  // 序列耗尽
+	backwards bool  // 时钟回拨
+	last      int64 // 上次产生 ID 的毫秒时间
+	now       int64 // 本次产生 ID 的毫秒时间
+}
+
+// notify 通知观察者
+func (e event) notify(o Observer) {
+	if e.exhausted {
+		o.OnSequenceExhausted(e.last)
+	}
+	if e.backwards {
+		o.OnClockBackwards(e.last, e.now)
+	}
+}
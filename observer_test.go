@@ -0,0 +1,86 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordObserver struct {
+	NopObserver
+	mu        sync.Mutex
+	issued    int
+	exhausted int
+	backwards int
+	warned    bool
+	node      int64
+	source    string
+}
+
+func (r *recordObserver) OnIssued(ID) {
+	r.mu.Lock()
+	r.issued++
+	r.mu.Unlock()
+}
+
+func (r *recordObserver) OnSequenceExhausted(int64) {
+	r.mu.Lock()
+	r.exhausted++
+	r.mu.Unlock()
+}
+
+func (r *recordObserver) OnClockBackwards(last, now int64) {
+	r.mu.Lock()
+	r.backwards++
+	r.mu.Unlock()
+}
+
+func (r *recordObserver) OnLifetimeWarning(time.Time, time.Duration) {
+	r.warned = true
+}
+
+func (r *recordObserver) OnNodeAcquired(node int64, source string) {
+	r.node, r.source = node, source
+}
+
+func TestObserver(t *testing.T) {
+	ob := &recordObserver{}
+	sf := MustNew(Node(7), SeqBits(1), Observe(ob))
+	if ob.node != 7 || ob.source != NodeSourceOption {
+		t.Fatalf("OnNodeAcquired got node=%d source=%q", ob.node, ob.source)
+	}
+	if ob.warned {
+		t.Fatal("unexpected OnLifetimeWarning")
+	}
+
+	for i := 0; i < 10; i++ {
+		sf.ID()
+	}
+	if ob.issued != 10 {
+		t.Fatalf("OnIssued called %d times, expected 10", ob.issued)
+	}
+	if ob.exhausted == 0 {
+		t.Fatal("OnSequenceExhausted not called with 1 bit sequence")
+	}
+
+	// 模拟时钟回拨
+	sf.mu.Lock()
+	sf.time += 60000
+	sf.mu.Unlock()
+	sf.ID()
+	if ob.backwards != 1 {
+		t.Fatalf("OnClockBackwards called %d times, expected 1", ob.backwards)
+	}
+}
+
+func TestObserverLifetimeWarning(t *testing.T) {
+	ob := &recordObserver{}
+	opts := []Option{NodeBits(11), SeqBits(11)}
+	maxTime := MustNew(opts...).MaxTime()
+	// 剩余寿命 1 天
+	startTime := Epoch(time.Now()) - maxTime + int64(24*time.Hour/time.Millisecond)
+	MustNew(append(opts, StartTime(startTime), Observe(ob))...)
+	if !ob.warned {
+		t.Fatal("OnLifetimeWarning not called")
+	}
+}
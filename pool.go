@@ -0,0 +1,123 @@
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// NodeAllocator 节点值分配器
+type NodeAllocator interface {
+	// Allocate 分配 n 个互不相同的节点值
+	Allocate(n int) ([]int64, error)
+}
+
+// NodeRange 连续节点值区间 [From, To]
+type NodeRange struct {
+	From int64
+	To   int64
+}
+
+// Allocate 从区间起始位置依次分配 n 个节点值
+func (r NodeRange) Allocate(n int) ([]int64, error) {
+	if n <= 0 || r.From < 0 || r.To < r.From || r.To-r.From+1 < int64(n) {
+		return nil, fmt.Errorf("Node range [%d, %d] can not allocate %d nodes", r.From, r.To, n)
+	}
+	nodes := make([]int64, n)
+	for i := range nodes {
+		nodes[i] = r.From + int64(i)
+	}
+	return nodes, nil
+}
+
+// PoolStrategy 生成器池分发策略
+type PoolStrategy uint8
+
+const (
+	RoundRobin PoolStrategy = iota // 依次轮询各生成器
+	Affinity                       // 尽量让同一处理器上的协程使用同一生成器, 减少锁竞争, 实际使用的生成器数量不超过 GOMAXPROCS
+)
+
+// Pool 生成器池, 持有多个不同节点值的 Snowflake 实例并分发调用
+// 吞吐量可突破单节点每毫秒序列容量限制, 但不同生成器产生的 ID 之间不保证递增
+type Pool struct {
+	next     uint64 // 轮询计数
+	sfs      []*Snowflake
+	strategy PoolStrategy
+	local    sync.Pool
+}
+
+// NewPool 创建包含 size 个生成器的池, 节点值由 alloc 分配, 其余配置由 opts 指定
+// 分配的节点值原样使用, 节点值 0 也不会被环境变量或私有 IP 替换
+func NewPool(alloc NodeAllocator, size int, strategy PoolStrategy, opts ...Option) (*Pool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("Pool size(%d) must be at least 1", size)
+	}
+	nodes, err := alloc.Allocate(size)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) != size {
+		return nil, fmt.Errorf("Node allocator returned %d nodes, expected %d", len(nodes), size)
+	}
+
+	p := Pool{sfs: make([]*Snowflake, size), strategy: strategy}
+	seen := make(map[int64]bool, size)
+	for i, node := range nodes {
+		if seen[node] {
+			return nil, fmt.Errorf("Node number(%d) allocated more than once", node)
+		}
+		seen[node] = true
+		sf, err := New(append(opts[:len(opts):len(opts)], fixedNode(node))...)
+		if err != nil {
+			return nil, err
+		}
+		p.sfs[i] = sf
+	}
+	p.local.New = func() interface{} {
+		return p.roundRobin()
+	}
+	return &p, nil
+}
+
+// ID 产生 ID
+func (p *Pool) ID() ID {
+	if p.strategy == Affinity {
+		sf := p.local.Get().(*Snowflake)
+		id := sf.ID()
+		p.local.Put(sf)
+		return id
+	}
+	return p.roundRobin().ID()
+}
+
+// Batch 使用池中一个生成器批量产生 n 个 ID
+func (p *Pool) Batch(n int) []ID {
+	if p.strategy == Affinity {
+		sf := p.local.Get().(*Snowflake)
+		ids := sf.Batch(n)
+		p.local.Put(sf)
+		return ids
+	}
+	return p.roundRobin().Batch(n)
+}
+
+// Size 返回池中生成器数量
+func (p *Pool) Size() int {
+	return len(p.sfs)
+}
+
+// Nodes 返回池中各生成器节点值
+func (p *Pool) Nodes() []int64 {
+	nodes := make([]int64, len(p.sfs))
+	for i, sf := range p.sfs {
+		nodes[i] = sf.Node()
+	}
+	return nodes
+}
+
+// roundRobin 轮询选择生成器
+func (p *Pool) roundRobin() *Snowflake {
+	n := atomic.AddUint64(&p.next, 1)
+	return p.sfs[n%uint64(len(p.sfs))]
+}
@@ -0,0 +1,127 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNodeRange(t *testing.T) {
+	nodes, err := NodeRange{From: 0, To: 3}.Allocate(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, node := range nodes {
+		if node != int64(i) {
+			t.Fatalf("expected node %d, got %d", i, node)
+		}
+	}
+	if _, err := (NodeRange{From: 0, To: 3}).Allocate(5); err == nil {
+		t.Fatal("no error allocating 5 nodes from 4")
+	}
+	if _, err := (NodeRange{From: 2, To: 1}).Allocate(1); err == nil {
+		t.Fatal("no error allocating from empty range")
+	}
+}
+
+func TestPool(t *testing.T) {
+	for _, strategy := range []PoolStrategy{RoundRobin, Affinity} {
+		p, err := NewPool(NodeRange{From: 0, To: 7}, 4, strategy, SeqBits(8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Size() != 4 {
+			t.Fatalf("expected size 4, got %d", p.Size())
+		}
+		for i, node := range p.Nodes() {
+			if node != int64(i) {
+				t.Fatalf("expected node %d, got %d", i, node)
+			}
+		}
+
+		var mu sync.Mutex
+		seen := make(map[ID]struct{})
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids := p.Batch(100)
+				for i := 0; i < 5000; i++ {
+					ids = append(ids, p.ID())
+				}
+				mu.Lock()
+				for _, id := range ids {
+					if _, ok := seen[id]; ok {
+						t.Errorf("duplicate id %d", id)
+					}
+					seen[id] = struct{}{}
+				}
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		if len(seen) != 8*5100 {
+			t.Fatalf("expected %d unique ids, got %d", 8*5100, len(seen))
+		}
+	}
+}
+
+func TestPoolInvalid(t *testing.T) {
+	if _, err := NewPool(NodeRange{From: 1020, To: 1030}, 8, RoundRobin); err == nil {
+		t.Fatal("no error with node out of range")
+	}
+	if _, err := NewPool(NodeRange{From: 0, To: 3}, 8, RoundRobin); err == nil {
+		t.Fatal("no error with too small node range")
+	}
+	for _, size := range []int{0, -1} {
+		if _, err := NewPool(emptyAllocator{}, size, RoundRobin); err == nil {
+			t.Fatalf("no error with pool size %d", size)
+		}
+	}
+}
+
+// emptyAllocator 按要求数量返回节点值, 数量不为正时返回空切片
+type emptyAllocator struct{}
+
+func (emptyAllocator) Allocate(n int) ([]int64, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	return NodeRange{From: 0, To: int64(n - 1)}.Allocate(n)
+}
+
+func BenchmarkGenerateParallel(b *testing.B) {
+	sf, _ := New(Node(1))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = sf.ID()
+		}
+	})
+}
+
+func BenchmarkPoolRoundRobinParallel(b *testing.B) {
+	p, _ := NewPool(NodeRange{From: 0, To: 1023}, 8, RoundRobin)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = p.ID()
+		}
+	})
+}
+
+func BenchmarkPoolAffinityParallel(b *testing.B) {
+	p, _ := NewPool(NodeRange{From: 0, To: 1023}, 8, Affinity)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = p.ID()
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package segment
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore 基于 JSON 文件的号段存储, 文件内容为 key 到已分配最大值的映射
+// 仅保证同一进程内并发安全, 多进程不能共享同一文件
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore 创建文件号段存储, 文件不存在时自动创建, 所有 key 从 1 开始分配
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Lease 租用号段
+func (s *FileStore) Lease(ctx context.Context, key string, step int64) (int64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maxIDs := make(map[string]int64)
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, 0, err
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &maxIDs); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	start := maxIDs[key] + 1
+	maxIDs[key] += step
+	if b, err = json.Marshal(maxIDs); err != nil {
+		return 0, 0, err
+	}
+	if err := writeFile(s.path, b); err != nil {
+		return 0, 0, err
+	}
+	return start, start + step, nil
+}
+
+// writeFile 先写入临时文件再重命名, 避免写入中断损坏原文件
+func writeFile(path string, b []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
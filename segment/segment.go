@@ -0,0 +1,142 @@
+// Package segment 号段模式 ID 生成器
+//
+// 生成器从 RangeStore 批量租用连续号段 (默认每次 1000 个), 在内存中依次分配,
+// 产生严格递增且稠密的整数 ID, 不包含时间语义. 当前号段使用超过 10% 时后台预取下一号段 (双缓冲),
+// 号段切换时无需等待存储.
+// 生成的 ID 与 snowflake.ID 类型相同, 可直接使用其编码及 JSON 处理.
+package segment
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/teamlint/snowflake"
+)
+
+const (
+	DefaultStep int64 = 1000 // 默认号段长度
+	prefetchPct int64 = 10   // 号段使用超过此百分比时预取下一号段
+)
+
+var (
+	// ErrUnknownKey is returned by RangeStore.Lease when the key does not exist
+	ErrUnknownKey = errors.New("segment: unknown key")
+	// ErrInvalidRange is returned when a RangeStore leases an empty or overlapping range
+	ErrInvalidRange = errors.New("segment: invalid range")
+)
+
+// RangeStore 号段存储
+type RangeStore interface {
+	// Lease 为 key 租用长度为 step 的号段, 返回半开区间 [start, end)
+	// 同一 key 每次租用的号段必须大于之前租用的所有号段
+	Lease(ctx context.Context, key string, step int64) (start, end int64, err error)
+}
+
+// Options 配置项
+type Options struct {
+	step int64 // 号段长度
+}
+
+type Option func(*Options)
+
+// Step 设置每次租用的号段长度
+func Step(step int64) Option {
+	return func(o *Options) {
+		o.step = step
+	}
+}
+
+// Generator 号段 ID 生成器
+type Generator struct {
+	store RangeStore
+	key   string
+	step  int64
+
+	mu      sync.Mutex
+	cur     *segment      // 当前号段
+	next    *segment      // 预取号段
+	loading chan struct{} // 正在租用号段时非 nil, 租用完成后关闭
+	err     error         // 最近一次预取错误
+	last    int64         // 最近一次租用号段的结束值
+}
+
+// segment 号段, 待分配值 value, 结束值 end (不包含)
+type segment struct {
+	start, value, end int64
+}
+
+// New 创建号段 ID 生成器
+func New(store RangeStore, key string, opts ...Option) (*Generator, error) {
+	options := Options{step: DefaultStep}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.step <= 0 {
+		return nil, errors.New("segment: step must be greater than 0")
+	}
+	return &Generator{store: store, key: key, step: options.step}, nil
+}
+
+// Key 返回生成器业务标识
+func (g *Generator) Key() string {
+	return g.key
+}
+
+// Next 产生 ID, 当前及预取号段均耗尽时同步租用号段
+func (g *Generator) Next(ctx context.Context) (snowflake.ID, error) {
+	g.mu.Lock()
+	for {
+		if s := g.cur; s != nil && s.value < s.end {
+			id := s.value
+			s.value++
+			if g.next == nil && g.loading == nil && (s.value-s.start)*100 >= (s.end-s.start)*prefetchPct {
+				g.load()
+			}
+			g.mu.Unlock()
+			return snowflake.ID(id), nil
+		}
+		if g.next != nil {
+			g.cur, g.next = g.next, nil
+			continue
+		}
+		if g.loading == nil {
+			if err := g.err; err != nil {
+				g.err = nil
+				g.mu.Unlock()
+				return 0, err
+			}
+			g.load()
+		}
+		loading := g.loading
+		g.mu.Unlock()
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		g.mu.Lock()
+	}
+}
+
+// load 后台租用号段, 调用时必须持有锁
+func (g *Generator) load() {
+	done := make(chan struct{})
+	g.loading = done
+	go func() {
+		start, end, err := g.store.Lease(context.Background(), g.key, g.step)
+		g.mu.Lock()
+		if err == nil && (end <= start || start < g.last) {
+			err = ErrInvalidRange
+		}
+		if err != nil {
+			g.err = err
+		} else {
+			g.last = end
+			g.next = &segment{start: start, value: start, end: end}
+		}
+		g.loading = nil
+		g.mu.Unlock()
+		close(done)
+	}()
+}
@@ -0,0 +1,152 @@
+package segment
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teamlint/snowflake"
+)
+
+type memStore struct {
+	mu     sync.Mutex
+	max    map[string]int64
+	leases int
+	delay  time.Duration
+}
+
+func (s *memStore) Lease(ctx context.Context, key string, step int64) (int64, int64, error) {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	max, ok := s.max[key]
+	if !ok {
+		return 0, 0, ErrUnknownKey
+	}
+	s.max[key] = max + step
+	s.leases++
+	return max + 1, max + step + 1, nil
+}
+
+func TestGenerator(t *testing.T) {
+	store := &memStore{max: map[string]int64{"order": 0}, delay: time.Millisecond}
+	g, err := New(store, "order", Step(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	var last snowflake.ID
+	for i := 1; i <= 1000; i++ {
+		id, err := g.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != snowflake.ID(i) {
+			t.Fatalf("expected id %d, got %d", i, id)
+		}
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+	}
+	store.mu.Lock()
+	leases := store.leases
+	store.mu.Unlock()
+	if leases < 10 || leases > 11 {
+		t.Fatalf("expected 10 or 11 leases, got %d", leases)
+	}
+}
+
+func TestGeneratorConcurrent(t *testing.T) {
+	store := &memStore{max: map[string]int64{"order": 5000}}
+	g, _ := New(store, "order", Step(50))
+
+	var mu sync.Mutex
+	seen := make(map[snowflake.ID]struct{})
+	var wg sync.WaitGroup
+	for j := 0; j < 8; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var last snowflake.ID
+			for i := 0; i < 1000; i++ {
+				id, err := g.Next(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if id <= last {
+					t.Errorf("id %d is not greater than %d", id, last)
+				}
+				last = id
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 8000 {
+		t.Fatalf("expected 8000 unique ids, got %d", len(seen))
+	}
+	for id := range seen {
+		if id <= 5000 || id > 13000 {
+			t.Fatalf("id %d out of dense range", id)
+		}
+	}
+}
+
+func TestGeneratorError(t *testing.T) {
+	store := &memStore{max: map[string]int64{}}
+	g, _ := New(store, "missing")
+	if _, err := g.Next(context.Background()); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+	if _, err := New(store, "order", Step(0)); err == nil {
+		t.Fatal("no error with step 0")
+	}
+
+	store.delay = 100 * time.Millisecond
+	store.max["slow"] = 0
+	g, _ = New(store, "slow")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := g.Next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "segment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "segment.json")
+
+	g, _ := New(NewFileStore(path), "user", Step(10))
+	for i := 1; i <= 25; i++ {
+		id, err := g.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != snowflake.ID(i) {
+			t.Fatalf("expected id %d, got %d", i, id)
+		}
+	}
+
+	// 重新打开文件, 继续之前已分配的最大值
+	start, end, err := NewFileStore(path).Lease(context.Background(), "user", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start <= 25 || end != start+10 {
+		t.Fatalf("unexpected range [%d, %d)", start, end)
+	}
+}
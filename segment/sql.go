@@ -0,0 +1,82 @@
+package segment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// DefaultTable 默认号段表名, 建表语句见 sql/segment.sql
+const DefaultTable = "snowflake_segment"
+
+// ErrInvalidTable is returned by NewSQLStore when the table name is not a plain, optionally schema qualified, identifier
+var ErrInvalidTable = errors.New("segment: invalid table name")
+
+// tableName 表名格式, 可带有 schema 前缀, 如 public.snowflake_segment
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLStore 基于 database/sql 的号段存储
+// 号段表每个 key 一行, biz_tag 为 key, max_id 为已分配最大值, 需预先插入记录
+type SQLStore struct {
+	db     *sql.DB
+	update string // 增加 max_id 语句
+	query  string // 读取 max_id 语句
+}
+
+// NewSQLStore 创建数据库号段存储, table 为空时使用 DefaultTable
+// table 仅允许字母, 数字及下划线组成的标识符, 可带有 schema 前缀, 拼接到 SQL 语句中
+// dollar 为 true 时使用 PostgreSQL 风格 $n 占位符, 否则使用 ?
+func NewSQLStore(db *sql.DB, table string, dollar bool) (*SQLStore, error) {
+	if table == "" {
+		table = DefaultTable
+	}
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTable, table)
+	}
+	placeholder := func(n int) string {
+		if dollar {
+			return fmt.Sprintf("$%d", n)
+		}
+		return "?"
+	}
+	return &SQLStore{
+		db:     db,
+		update: fmt.Sprintf("UPDATE %s SET max_id = max_id + %s WHERE biz_tag = %s", table, placeholder(1), placeholder(2)),
+		query:  fmt.Sprintf("SELECT max_id FROM %s WHERE biz_tag = %s", table, placeholder(1)),
+	}, nil
+}
+
+// Lease 在事务中增加 max_id 并读取新值, 租用号段
+func (s *SQLStore) Lease(ctx context.Context, key string, step int64) (start int64, end int64, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, s.update, step, key)
+	if err != nil {
+		return 0, 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, 0, err
+	} else if n == 0 {
+		return 0, 0, ErrUnknownKey
+	}
+
+	var maxID int64
+	err = tx.QueryRowContext(ctx, s.query, key).Scan(&maxID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return maxID - step + 1, maxID + 1, nil
+}
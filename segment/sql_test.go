@@ -0,0 +1,189 @@
+package segment
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+//******************************************************************************
+// Fake driver, UPDATE 增加 max_id, SELECT 返回 max_id, 记录执行的语句及事务结果
+
+type fakeDriver struct {
+	mu        sync.Mutex
+	max       map[string]int64
+	queries   []string
+	commits   int
+	rollbacks int
+	failQuery bool
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+type fakeTx struct{ d *fakeDriver }
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+type fakeRows struct {
+	values []int64
+	i      int
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("segment-fake", fake)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return &fakeTx{c.d}, nil }
+
+func (tx *fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+	key := args[1].(string)
+	max, ok := d.max[key]
+	if !ok {
+		return driver.RowsAffected(0), nil
+	}
+	d.max[key] = max + args[0].(int64)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+	if d.failQuery {
+		return nil, errors.New("query failed")
+	}
+	return &fakeRows{values: []int64{d.max[args[0].(string)]}}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"max_id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	dest[0] = r.values[r.i]
+	r.i++
+	return nil
+}
+
+func openFakeDB(t *testing.T, max map[string]int64) *sql.DB {
+	t.Helper()
+	fake.mu.Lock()
+	fake.max, fake.queries, fake.commits, fake.rollbacks, fake.failQuery = max, nil, 0, 0, false
+	fake.mu.Unlock()
+	db, err := sql.Open("segment-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLStore(t *testing.T) {
+	tests := []struct {
+		dollar bool
+		table  string
+		update string
+		query  string
+	}{
+		{true, "", "UPDATE snowflake_segment SET max_id = max_id + $1 WHERE biz_tag = $2", "SELECT max_id FROM snowflake_segment WHERE biz_tag = $1"},
+		{false, "ids.segment", "UPDATE ids.segment SET max_id = max_id + ? WHERE biz_tag = ?", "SELECT max_id FROM ids.segment WHERE biz_tag = ?"},
+	}
+	for _, tt := range tests {
+		db := openFakeDB(t, map[string]int64{"order": 100})
+		store, err := NewSQLStore(db, tt.table, tt.dollar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start, end, err := store.Lease(context.Background(), "order", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if start != 101 || end != 111 {
+			t.Fatalf("lease = [%d, %d), want [101, 111)", start, end)
+		}
+		if start, end, _ = store.Lease(context.Background(), "order", 10); start != 111 || end != 121 {
+			t.Fatalf("lease = [%d, %d), want [111, 121)", start, end)
+		}
+
+		fake.mu.Lock()
+		queries, commits, rollbacks := fake.queries, fake.commits, fake.rollbacks
+		fake.mu.Unlock()
+		if len(queries) != 4 || queries[0] != tt.update || queries[1] != tt.query {
+			t.Fatalf("queries = %q", queries)
+		}
+		if commits != 2 || rollbacks != 0 {
+			t.Fatalf("commits = %d, rollbacks = %d, want 2 and 0", commits, rollbacks)
+		}
+	}
+}
+
+func TestSQLStoreError(t *testing.T) {
+	db := openFakeDB(t, map[string]int64{"order": 0})
+	store, err := NewSQLStore(db, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Lease(context.Background(), "user", 10); err != ErrUnknownKey {
+		t.Fatalf("error = %v, want ErrUnknownKey", err)
+	}
+
+	fake.mu.Lock()
+	fake.failQuery = true
+	fake.mu.Unlock()
+	if _, _, err := store.Lease(context.Background(), "order", 10); err == nil {
+		t.Fatal("expected query error")
+	}
+
+	fake.mu.Lock()
+	commits, rollbacks := fake.commits, fake.rollbacks
+	fake.mu.Unlock()
+	if commits != 0 || rollbacks != 2 {
+		t.Fatalf("commits = %d, rollbacks = %d, want 0 and 2", commits, rollbacks)
+	}
+
+	for _, table := range []string{"segment; DROP TABLE users", "a.b.c", "1table", `"segment"`} {
+		if _, err := NewSQLStore(db, table, true); !errors.Is(err, ErrInvalidTable) {
+			t.Fatalf("table %q error = %v, want ErrInvalidTable", table, err)
+		}
+	}
+	if _, err := NewSQLStore(db, "segment_2", false); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,140 @@
+package snowflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	SignatureLen = 12 // 签名标签字节数, HMAC-SHA256 截断
+	MinSignerKey = 16 // 最小密钥长度
+)
+
+const tokenSeparator = "."
+
+var (
+	// ErrInvalidToken is returned by Signer.Verify when the token is malformed
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrUnknownKey is returned by Signer.Verify when the token key ID is not registered
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrSignature is returned by Signer.Verify when the signature does not match
+	ErrSignature = errors.New("invalid signature")
+	// ErrInvalidKeyID is returned by Signer when the key ID is empty or contains "."
+	ErrInvalidKeyID = errors.New("invalid key id")
+)
+
+// Signer 为 ID 附加截断的 HMAC 签名, 产生防篡改的公开令牌
+// 令牌格式为 密钥ID.Base64URL(ID).Base64URL(签名), 密钥 ID 用于密钥轮换
+// 轮换时使用 Rotate 设置新的签名密钥, 旧密钥保留用于验证, 过期后使用 RemoveKey 删除
+type Signer struct {
+	mu      sync.RWMutex
+	current string            // 签名密钥 ID
+	keys    map[string][]byte // 所有可验证的密钥
+}
+
+// NewSigner 创建签名器, kid 为签名密钥 ID
+func NewSigner(kid string, key []byte) (*Signer, error) {
+	s := Signer{keys: make(map[string][]byte)}
+	if err := s.Rotate(kid, key); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// AddKey 添加仅用于验证的密钥, 密钥短于 MinSignerKey 时返回 ErrShortKey
+func (s *Signer) AddKey(kid string, key []byte) error {
+	if kid == "" || strings.Contains(kid, tokenSeparator) {
+		return ErrInvalidKeyID
+	}
+	if len(key) < MinSignerKey {
+		return ErrShortKey
+	}
+	s.mu.Lock()
+	s.keys[kid] = append([]byte(nil), key...)
+	s.mu.Unlock()
+	return nil
+}
+
+// Rotate 添加密钥并设为签名密钥
+func (s *Signer) Rotate(kid string, key []byte) error {
+	if err := s.AddKey(kid, key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.current = kid
+	s.mu.Unlock()
+	return nil
+}
+
+// RemoveKey 删除验证密钥, 不能删除当前签名密钥
+func (s *Signer) RemoveKey(kid string) {
+	s.mu.Lock()
+	if kid != s.current {
+		delete(s.keys, kid)
+	}
+	s.mu.Unlock()
+}
+
+// Sign 使用当前签名密钥签名 ID, 返回令牌
+func (s *Signer) Sign(id ID) string {
+	s.mu.RLock()
+	kid, key := s.current, s.keys[s.current]
+	s.mu.RUnlock()
+
+	tag := sign(key, kid, id)
+	b := make([]byte, 0, len(kid)+2+Base64URLLen+base64.RawURLEncoding.EncodedLen(SignatureLen))
+	b = append(b, kid...)
+	b = append(b, tokenSeparator...)
+	b = id.AppendBase64URL(b)
+	b = append(b, tokenSeparator...)
+	n := len(b)
+	b = grow(b, base64.RawURLEncoding.EncodedLen(SignatureLen))
+	base64.RawURLEncoding.Encode(b[n:], tag[:SignatureLen])
+	return string(b)
+}
+
+// Verify 验证令牌签名, 返回其中的 ID
+func (s *Signer) Verify(token string) (ID, error) {
+	parts := strings.Split(token, tokenSeparator)
+	if len(parts) != 3 {
+		return -1, ErrInvalidToken
+	}
+	id, err := ParseBase64URL(parts[1])
+	if err != nil {
+		return -1, ErrInvalidToken
+	}
+	// ID 及签名均只接受规范编码, 令牌不可被改写为等价形式
+	if len(parts[2]) != base64.RawURLEncoding.EncodedLen(SignatureLen) {
+		return -1, ErrInvalidToken
+	}
+	tag, err := base64URLStrict.DecodeString(parts[2])
+	if err != nil || len(tag) != SignatureLen {
+		return -1, ErrInvalidToken
+	}
+
+	s.mu.RLock()
+	key, ok := s.keys[parts[0]]
+	s.mu.RUnlock()
+	if !ok {
+		return -1, ErrUnknownKey
+	}
+	expected := sign(key, parts[0], id)
+	if !hmac.Equal(tag, expected[:SignatureLen]) {
+		return -1, ErrSignature
+	}
+	return id, nil
+}
+
+// sign 计算 HMAC-SHA256(key, kid + "." + IntBytes)
+func sign(key []byte, kid string, id ID) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(kid))
+	mac.Write([]byte(tokenSeparator))
+	b := id.IntBytes()
+	mac.Write(b[:])
+	return mac.Sum(nil)
+}
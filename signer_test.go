@@ -0,0 +1,107 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+)
+
+const base64URLAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+func TestSigner(t *testing.T) {
+	if _, err := NewSigner("k1", []byte("short")); err != ErrShortKey {
+		t.Fatalf("Expected ErrShortKey, got %v", err)
+	}
+	if _, err := NewSigner("k.1", []byte("0123456789abcdef")); err != ErrInvalidKeyID {
+		t.Fatalf("Expected ErrInvalidKeyID, got %v", err)
+	}
+
+	s, err := NewSigner("k1", []byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := MustNew(Node(1)).ID()
+	token := s.Sign(id)
+	if !strings.HasPrefix(token, "k1."+id.Base64URL()+".") {
+		t.Fatalf("Sign(%d) = %q", id, token)
+	}
+	pID, err := s.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pID != id {
+		t.Fatalf("pID %v != id %v", pID, id)
+	}
+
+	// 篡改 ID
+	forged := "k1." + (id + 1).Base64URL() + token[strings.LastIndex(token, "."):]
+	if _, err := s.Verify(forged); err != ErrSignature {
+		t.Fatalf("Expected ErrSignature, got %v", err)
+	}
+	// 篡改签名
+	b := []byte(token)
+	if b[len(b)-2] == 'A' {
+		b[len(b)-2] = 'B'
+	} else {
+		b[len(b)-2] = 'A'
+	}
+	if _, err := s.Verify(string(b)); err != ErrSignature {
+		t.Fatalf("Expected ErrSignature, got %v", err)
+	}
+
+	// 修改 ID 编码末尾字符的填充位, 令牌不可改写
+	idPart := id.Base64URL()
+	last := strings.IndexByte(base64URLAlphabet, idPart[len(idPart)-1])
+	spare := idPart[:len(idPart)-1] + string(base64URLAlphabet[last^1])
+	if _, err := s.Verify(strings.Replace(token, idPart, spare, 1)); err != ErrInvalidToken {
+		t.Fatalf("Verify with spare bit flipped: expected ErrInvalidToken, got %v", err)
+	}
+	if _, err := s.Verify("k1.AAAAAAAANRN" + s.Sign(13587)[len("k1.AAAAAAAANRM"):]); err != ErrInvalidToken {
+		t.Fatalf("Verify with spare bit flipped: expected ErrInvalidToken, got %v", err)
+	}
+	sig := token[strings.LastIndex(token, ".")+1:]
+	if _, err := s.Verify(token[:len(token)-len(sig)] + sig[:8] + "\n" + sig[8:]); err != ErrInvalidToken {
+		t.Fatalf("Verify with newline in signature: expected ErrInvalidToken, got %v", err)
+	}
+
+	for _, tk := range []string{"", "k1", "k1." + id.Base64URL(), "k1.x.y", token + ".x", "k1." + id.Base64URL() + ".!!"} {
+		if _, err := s.Verify(tk); err != ErrInvalidToken {
+			t.Fatalf("Verify(%q): expected ErrInvalidToken, got %v", tk, err)
+		}
+	}
+	if _, err := s.Verify("k9" + token[2:]); err != ErrUnknownKey {
+		t.Fatalf("Expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestSignerRotate(t *testing.T) {
+	s, _ := NewSigner("k1", []byte("0123456789abcdef"))
+	id := MustNew(Node(1)).ID()
+	old := s.Sign(id)
+
+	if err := s.Rotate("k2", []byte("fedcba9876543210")); err != nil {
+		t.Fatal(err)
+	}
+	token := s.Sign(id)
+	if !strings.HasPrefix(token, "k2.") {
+		t.Fatalf("Sign after Rotate = %q, expected key k2", token)
+	}
+	// 旧令牌仍可验证
+	for _, tk := range []string{old, token} {
+		if pID, err := s.Verify(tk); err != nil || pID != id {
+			t.Fatalf("Verify(%q) = %d, %v", tk, pID, err)
+		}
+	}
+	// 使用其他密钥 ID 重签无效
+	if _, err := s.Verify("k2" + old[2:]); err != ErrSignature {
+		t.Fatalf("Expected ErrSignature, got %v", err)
+	}
+
+	s.RemoveKey("k1")
+	if _, err := s.Verify(old); err != ErrUnknownKey {
+		t.Fatalf("Expected ErrUnknownKey, got %v", err)
+	}
+	s.RemoveKey("k2")
+	if _, err := s.Verify(token); err != nil {
+		t.Fatalf("current key removed: %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package snowflake
 
 import (
-	"encoding/base64"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -10,6 +9,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -41,6 +41,10 @@ type Options struct {
 	timeBits uint8 // 时间位数, 默认 43 位
 	nodeBits uint8 // 节点位数, 默认 10 位
 	seqBits  uint8 // 递增序列位数, 默认 10 位
+
+	observer Observer // 事件观察者
+
+	nodeFixed bool // 节点值固定, 为 0 时不查找环境变量及私有 IP
 }
 
 type Option func(*Options)
@@ -68,6 +72,10 @@ const (
 	encodeBase32Map = "ybndrfg8ejkmcpqxot1uwisza345h769"
 	// Base58
 	encodeBase58Map = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	// Base62, 按 ASCII 顺序排列
+	encodeBase62Map = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Crockford Base32, 按 ASCII 顺序排列
+	encodeCrockfordMap = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
 )
 
 var (
@@ -79,13 +87,37 @@ var (
 	decodeBase58Map [256]byte
 	// ErrInvalidBase58 is returned by ParseBase58 when given an invalid []byte
 	ErrInvalidBase58 = errors.New("invalid base58")
+	// Base62
+	decodeBase62Map [256]byte
+	// ErrInvalidBase62 is returned by ParseBase62 when given an invalid string
+	ErrInvalidBase62 = errors.New("invalid base62")
+	// Crockford Base32
+	decodeCrockfordMap [256]byte
+	// ErrInvalidCrockford is returned by ParseBase32Crockford when given an invalid string
+	ErrInvalidCrockford = errors.New("invalid crockford base32")
+	// Decimal
+	decodeDecimalMap [256]byte
+	// ErrInvalidDecimal is returned by ParsePaddedString when given an invalid string
+	ErrInvalidDecimal = errors.New("invalid decimal")
+	// ErrInvalidBinary is returned by UnmarshalBinary and GobDecode when given data is not 8 bytes
+	ErrInvalidBinary = errors.New("invalid binary")
+	// ErrEmpty is returned by parsers when given an empty input
+	ErrEmpty = errors.New("empty input")
+	// ErrInvalidLength is returned by parsers when given an input longer than any valid encoding or of wrong fixed length
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrOverflow is returned by parsers when the parsed value does not fit in an ID
+	ErrOverflow = errors.New("value overflows")
 )
 
 // A JSONSyntaxError is returned from UnmarshalJSON if an invalid ID is provided.
-type JSONSyntaxError struct{ original []byte }
+// Offset is the position of the offending byte in the original input.
+type JSONSyntaxError struct {
+	original []byte
+	Offset   int
+}
 
 func (j JSONSyntaxError) Error() string {
-	return fmt.Sprintf("invalid snowflake ID %q", string(j.original))
+	return fmt.Sprintf("invalid snowflake ID %q at offset %d", string(j.original), j.Offset)
 }
 
 //********************************************************************************
@@ -95,19 +127,48 @@ func init() {
 	// log
 	log.SetOutput(ioutil.Discard)
 	// Base32
-	for i := 0; i < len(encodeBase32Map); i++ {
+	for i := range decodeBase32Map {
 		decodeBase32Map[i] = 0xFF
 	}
 	for i := 0; i < len(encodeBase32Map); i++ {
 		decodeBase32Map[encodeBase32Map[i]] = byte(i)
 	}
 	// Base58
-	for i := 0; i < len(encodeBase58Map); i++ {
+	for i := range decodeBase58Map {
 		decodeBase58Map[i] = 0xFF
 	}
 	for i := 0; i < len(encodeBase58Map); i++ {
 		decodeBase58Map[encodeBase58Map[i]] = byte(i)
 	}
+	// Base62
+	for i := range decodeBase62Map {
+		decodeBase62Map[i] = 0xFF
+	}
+	for i := 0; i < len(encodeBase62Map); i++ {
+		decodeBase62Map[encodeBase62Map[i]] = byte(i)
+	}
+	// Decimal
+	for i := range decodeDecimalMap {
+		decodeDecimalMap[i] = 0xFF
+	}
+	for i := byte(0); i < 10; i++ {
+		decodeDecimalMap['0'+i] = i
+	}
+	// Crockford Base32, 解码不区分大小写, I L 视为 1, O 视为 0
+	for i := range decodeCrockfordMap {
+		decodeCrockfordMap[i] = 0xFF
+	}
+	for i := 0; i < len(encodeCrockfordMap); i++ {
+		c := encodeCrockfordMap[i]
+		decodeCrockfordMap[c] = byte(i)
+		decodeCrockfordMap[c|0x20] = byte(i)
+	}
+	for _, c := range "IiLl" {
+		decodeCrockfordMap[c] = 1
+	}
+	for _, c := range "Oo" {
+		decodeCrockfordMap[c] = 0
+	}
 }
 
 // New 创建 Snowflake 实例
@@ -130,20 +191,22 @@ func New(opts ...Option) (*Snowflake, error) {
 	if sf.elapsedTime() < 0 {
 		return nil, fmt.Errorf("Start time number(%d) must be before now's epoch(%d)", sf.opts.startTime, epoch(time.Now()))
 	}
-	sf.initNode()
+	source := sf.initNode()
 	if sf.node < 0 || sf.node > sf.nodeMax {
 		return nil, errors.New("Node number must be between 0 and " + strconv.FormatInt(sf.nodeMax, 10))
 	}
 
-	log.Println("+---------------------------- Snowflake -----------------------------------+")
-	log.Printf("| 1 Bit Unused | %2d Bit Timestamp |  %2d Bit NodeID  |   %2d Bit Sequence ID |\n",
-		sf.TimeBits(), sf.NodeBits(), sf.SeqBits())
-	log.Println("+--------------------------------------------------------------------------+")
-	log.Printf("Node = %d\n", sf.Node())
-	log.Printf("MaxTime = %d\tMaxNode = %d\tMaxseq = %d\n", sf.MaxTime(), sf.MaxNode(), sf.MaxSeq())
-	log.Printf("StartTime = %d\n", sf.StartTime())
-	log.Printf("StartStdTime = %v\n", sf.StartStdTime())
-	log.Printf("Lifetime = %v\n\n", sf.Lifetime())
+	for _, line := range strings.Split(sf.Banner(), "\n") {
+		log.Println(line)
+	}
+
+	if o := sf.opts.observer; o != nil {
+		o.OnNodeAcquired(sf.node, source)
+		lifetime := sf.Lifetime()
+		if remaining := time.Until(lifetime); remaining < LifetimeWarning {
+			o.OnLifetimeWarning(lifetime, remaining)
+		}
+	}
 
 	return &sf, nil
 }
@@ -175,6 +238,14 @@ func Node(node int64) Option {
 	}
 }
 
+// fixedNode 设置固定节点值, 节点值为 0 时也不使用环境变量及私有 IP
+func fixedNode(node int64) Option {
+	return func(o *Options) {
+		o.node = node
+		o.nodeFixed = true
+	}
+}
+
 // StartTime 设置节点 ID
 func StartTime(startTime int64) Option {
 	return func(o *Options) {
@@ -226,64 +297,37 @@ func ParseInt64(id int64) ID {
 
 // ParseString 转化字符串类型到 ID 类型
 func ParseString(id string) (ID, error) {
-	i, err := strconv.ParseInt(id, 10, 64)
-	return ID(i), err
-
+	return parseID(id, 10)
 }
 
 // ParseBase2 转化 Base2 编码字符串到 ID 类型
 func ParseBase2(id string) (ID, error) {
-	i, err := strconv.ParseInt(id, 2, 64)
-	return ID(i), err
+	return parseID(id, 2)
 }
 
 // ParseBase32 转化 Base32 编码字节数组到 ID 类型
 func ParseBase32(b []byte) (ID, error) {
-	var id int64
-
-	for i := range b {
-		if decodeBase32Map[b[i]] == 0xFF {
-			return -1, ErrInvalidBase32
-		}
-		id = id*32 + int64(decodeBase32Map[b[i]])
-	}
-
-	return ID(id), nil
+	return ParseBase32String(string(b))
 }
 
 // ParseBase36 转化 Base36 编码字符串到 ID 类型
 func ParseBase36(id string) (ID, error) {
-	i, err := strconv.ParseInt(id, 36, 64)
-	return ID(i), err
+	return parseID(id, 36)
 }
 
 // ParseBase58 转化 Base58 编码字节数组到 ID 类型
 func ParseBase58(b []byte) (ID, error) {
-	var id int64
-	for i := range b {
-		if decodeBase58Map[b[i]] == 0xFF {
-			return -1, ErrInvalidBase58
-		}
-		id = id*58 + int64(decodeBase58Map[b[i]])
-	}
-
-	return ID(id), nil
+	return ParseBase58String(string(b))
 }
 
 // ParseBase64 转化 Base64 编码字节数组到 ID 类型
 func ParseBase64(id string) (ID, error) {
-	b, err := base64.StdEncoding.DecodeString(id)
-	if err != nil {
-		return -1, err
-	}
-	return ParseBytes(b)
-
+	return ParseBase64Bytes([]byte(id))
 }
 
 // ParseBytes 转化字节数组到 ID 类型
 func ParseBytes(id []byte) (ID, error) {
-	i, err := strconv.ParseInt(string(id), 10, 64)
-	return ID(i), err
+	return parseID(string(id), 10)
 }
 
 // ParseIntBytes 转化 Big Endian 编码字节数组到 ID 类型
@@ -300,7 +344,7 @@ func epoch(t time.Time) int64 {
 	if t.IsZero() {
 		return DefaultStartTime
 	}
-	return t.UnixNano() / 1e6
+	return t.UnixMilli()
 }
 
 func toTime(epoch int64) time.Time {
@@ -348,27 +392,77 @@ func isPrivateIPv4(ip net.IP) bool {
 // ID 产生 ID
 func (sf *Snowflake) ID() ID {
 	sf.mu.Lock()
+	id, ev := sf.next()
+	sf.mu.Unlock()
+
+	if o := sf.opts.observer; o != nil {
+		ev.notify(o)
+		o.OnIssued(id)
+	}
+	return id
+}
+
+// Batch 批量产生 n 个 ID, 整批只加锁一次
+// 批量较大时会持有锁等待后续毫秒, 期间其他调用将被阻塞
+func (sf *Snowflake) Batch(n int) []ID {
+	if n <= 0 {
+		return nil
+	}
+	ids := make([]ID, n)
+	sf.fill(ids)
+	return ids
+}
+
+// fill 使用新产生的 ID 填充 ids
+func (sf *Snowflake) fill(ids []ID) {
+	o := sf.opts.observer
+	var evs []event
+
+	sf.mu.Lock()
+	for i := range ids {
+		var ev event
+		ids[i], ev = sf.next()
+		if o != nil && (ev.exhausted || ev.backwards) {
+			evs = append(evs, ev)
+		}
+	}
+	sf.mu.Unlock()
 
+	if o != nil {
+		for _, ev := range evs {
+			ev.notify(o)
+		}
+		for _, id := range ids {
+			o.OnIssued(id)
+		}
+	}
+}
+
+// next 产生下一个 ID, 调用时必须持有锁
+func (sf *Snowflake) next() (ID, event) {
+	ev := event{last: sf.time + sf.opts.startTime}
 	elapsedTime := sf.elapsedTime()
 	if sf.time == elapsedTime {
 		sf.seq = (sf.seq + 1) & sf.seqMask
 		// 如果当前序列超出10bit长度,即大于1023，则需要等待下一毫秒
 		// 下一毫秒将使用sequence:0
 		if sf.seq == 0 {
+			ev.exhausted = true
 			for elapsedTime <= sf.time {
 				elapsedTime = sf.elapsedTime()
 			}
 		}
 	} else {
+		ev.backwards = elapsedTime < sf.time
 		sf.seq = 0
 	}
 	sf.time = elapsedTime
+	ev.now = sf.time + sf.opts.startTime
 	id := sf.time<<(sf.opts.nodeBits+sf.opts.seqBits) |
 		sf.node<<sf.opts.seqBits |
 		sf.seq
 
-	sf.mu.Unlock()
-	return ID(id)
+	return ID(id), ev
 }
 
 // MaxTime 返回可生成的最大时间
@@ -421,6 +515,21 @@ func (sf *Snowflake) Node() int64 {
 	return sf.node
 }
 
+// Banner 返回 ID 布局及节点信息说明, Verbose 选项时 New 输出到日志
+func (sf *Snowflake) Banner() string {
+	var b strings.Builder
+	b.WriteString("+---------------------------- Snowflake -----------------------------------+\n")
+	fmt.Fprintf(&b, "| 1 Bit Unused | %2d Bit Timestamp |  %2d Bit NodeID  |   %2d Bit Sequence ID |\n",
+		sf.TimeBits(), sf.NodeBits(), sf.SeqBits())
+	b.WriteString("+--------------------------------------------------------------------------+\n")
+	fmt.Fprintf(&b, "Node = %d\n", sf.Node())
+	fmt.Fprintf(&b, "MaxTime = %d\tMaxNode = %d\tMaxseq = %d\n", sf.MaxTime(), sf.MaxNode(), sf.MaxSeq())
+	fmt.Fprintf(&b, "StartTime = %d\n", sf.StartTime())
+	fmt.Fprintf(&b, "StartStdTime = %v\n", sf.StartStdTime())
+	fmt.Fprintf(&b, "Lifetime = %v", sf.Lifetime())
+	return b.String()
+}
+
 // Lifetime 返回可生成的生命
 func (sf *Snowflake) Lifetime() time.Time {
 	return toTime(sf.MaxTime() + sf.opts.startTime)
@@ -469,25 +578,27 @@ func (sf *Snowflake) initBits() {
 	sf.seqMask = -1 ^ (-1 << sf.opts.seqBits)   // 4095, 序列段在最后一段,所以掩码和最大值是一样的
 }
 
-// initNode 初始化节点值
-func (sf *Snowflake) initNode() {
+// initNode 初始化节点值, 返回节点值来源
+func (sf *Snowflake) initNode() string {
 	sf.node = sf.opts.node
-	if sf.node == 0 {
+	if sf.node == 0 && !sf.opts.nodeFixed {
 		// 查找环境变量
 		if envVal, ok := os.LookupEnv(EnvNode); ok {
 			if val, err := strconv.ParseInt(envVal, 10, 64); err == nil {
 				sf.node = val & sf.nodeMax
 				// log.Printf("[initNode][%d](%d) env=%v, act=%v\n", sf.opts.nodeBits, sf.nodeMax, val, sf.node)
-				return
+				return NodeSourceEnv
 			}
 		}
 		// 查找主机私有 IP 地址, 作为节点值
 		if val, err := sf.ip2Node(); err == nil {
 			sf.node = val
 			// log.Printf("[initNode][%d](%d) ip=%v, act=%v\n", sf.opts.nodeBits, sf.nodeMax, val, sf.node)
-			return
+			return NodeSourceIP
 		}
+		return NodeSourceDefault
 	}
+	return NodeSourceOption
 }
 
 // ip2Node 使用私有 IP 作为节点值
@@ -560,27 +671,40 @@ func (f ID) Base2() string {
 
 // Base64 返回 Base64 编码 ID
 func (f ID) Base64() string {
-	return base64.StdEncoding.EncodeToString(f.Bytes())
+	var b [28]byte
+	return string(f.AppendBase64(b[:0]))
 }
 
-// Base32 返回Base32 编码 ID
+// Base64URL 返回 8 字节 Big Endian 编码的 URL 安全 Base64 编码 ID, 固定 11 个字符, 无填充
+func (f ID) Base64URL() string {
+	var b [Base64URLLen]byte
+	return string(f.AppendBase64URL(b[:0]))
+}
+
+// Base32 返回 Base32 编码 ID
+// 负数 ID 的编码见 AppendBase32
 func (f ID) Base32() string {
-	if f < 32 {
-		return string(encodeBase32Map[f])
-	}
+	var b [13]byte
+	return string(f.AppendBase32(b[:0]))
+}
 
-	b := make([]byte, 0, 12)
-	for f >= 32 {
-		b = append(b, encodeBase32Map[f%32])
-		f /= 32
-	}
-	b = append(b, encodeBase32Map[f])
+// Base62 返回 Base62 编码 ID
+// 负数 ID 的编码见 AppendBase62
+func (f ID) Base62() string {
+	var b [11]byte
+	return string(f.AppendBase62(b[:0]))
+}
 
-	for x, y := 0, len(b)-1; x < y; x, y = x+1, y-1 {
-		b[x], b[y] = b[y], b[x]
-	}
+// Base32Crockford 返回 Crockford Base32 编码 ID
+// 负数 ID 的编码见 AppendBase32Crockford
+func (f ID) Base32Crockford() string {
+	var b [13]byte
+	return string(f.AppendBase32Crockford(b[:0]))
+}
 
-	return string(b)
+// Hex 返回小写十六进制编码 ID
+func (f ID) Hex() string {
+	return strconv.FormatInt(int64(f), 16)
 }
 
 // Base36 返回 Base36 编码 ID
@@ -589,23 +713,10 @@ func (f ID) Base36() string {
 }
 
 // Base58 返回 Base58 编码 ID
+// 负数 ID 的编码见 AppendBase58
 func (f ID) Base58() string {
-	if f < 58 {
-		return string(encodeBase58Map[f])
-	}
-
-	b := make([]byte, 0, 11)
-	for f >= 58 {
-		b = append(b, encodeBase58Map[f%58])
-		f /= 58
-	}
-	b = append(b, encodeBase58Map[f])
-
-	for x, y := 0, len(b)-1; x < y; x, y = x+1, y-1 {
-		b[x], b[y] = b[y], b[x]
-	}
-
-	return string(b)
+	var b [11]byte
+	return string(f.AppendBase58(b[:0]))
 }
 
 // Bytes 返回字节数组类型 ID
@@ -629,17 +740,52 @@ func (f ID) MarshalJSON() ([]byte, error) {
 	return buff, nil
 }
 
-// UnmarshalJSON 转化 JSON 字节数组到 ID 类型
+// UnmarshalJSON 转化 JSON 字节数组到 ID 类型, 同时支持字符串 "123" 及数字 123 形式
 func (f *ID) UnmarshalJSON(b []byte) error {
-	if len(b) < 3 || b[0] != '"' || b[len(b)-1] != '"' {
-		return JSONSyntaxError{b}
+	id, err := parseJSON(b)
+	if err != nil {
+		return err
 	}
+	*f = id
+	return nil
+}
 
-	i, err := strconv.ParseInt(string(b[1:len(b)-1]), 10, 64)
+// MarshalText 实现 encoding.TextMarshaler 接口, 使用十进制字符串
+func (f ID) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(f), 10), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口, 转化十进制字符串到 ID 类型
+func (f *ID) UnmarshalText(b []byte) error {
+	id, err := ParseBytes(b)
 	if err != nil {
 		return err
 	}
+	*f = id
+	return nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口, 使用 Big Endian 编码 8 字节
+func (f ID) MarshalBinary() ([]byte, error) {
+	b := f.IntBytes()
+	return b[:], nil
+}
 
-	*f = ID(i)
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口, 转化 Big Endian 编码 8 字节到 ID 类型
+func (f *ID) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return ErrInvalidBinary
+	}
+	*f = ID(int64(binary.BigEndian.Uint64(b)))
 	return nil
 }
+
+// GobEncode 实现 gob.GobEncoder 接口, 与 MarshalBinary 相同
+func (f ID) GobEncode() ([]byte, error) {
+	return f.MarshalBinary()
+}
+
+// GobDecode 实现 gob.GobDecoder 接口, 与 UnmarshalBinary 相同
+func (f *ID) GobDecode(b []byte) error {
+	return f.UnmarshalBinary(b)
+}
@@ -2,8 +2,11 @@ package snowflake
 
 import (
 	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -116,6 +119,36 @@ func TestDuplicateID(t *testing.T) {
 	}
 }
 
+func TestBatch(t *testing.T) {
+	sf := MustNew(Node(1), SeqBits(4))
+
+	if ids := sf.Batch(0); ids != nil {
+		t.Fatalf("expected nil batch, got %v", ids)
+	}
+	ids := sf.Batch(1000)
+	if len(ids) != 1000 {
+		t.Fatalf("expected 1000 ids, got %d", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("id %d is not greater than %d", ids[i], ids[i-1])
+		}
+	}
+	if id := sf.ID(); id <= ids[len(ids)-1] {
+		t.Fatalf("id %d is not greater than %d", id, ids[len(ids)-1])
+	}
+}
+
+func TestBanner(t *testing.T) {
+	sf := MustNew(Node(5), NodeBits(16), SeqBits(6))
+	banner := sf.Banner()
+	for _, want := range []string{"41 Bit Timestamp", "16 Bit NodeID", " 6 Bit Sequence ID", "Node = 5\n", "MaxNode = 65535"} {
+		if !strings.Contains(banner, want) {
+			t.Fatalf("banner %q does not contain %q", banner, want)
+		}
+	}
+}
+
 func TestRace(t *testing.T) {
 	// opts := []Option{Node(1), SeqBits(8), Verbose()}
 	opts := []Option{Node(1), SeqBits(10)}
@@ -160,7 +193,11 @@ func TestPrintAll(t *testing.T) {
 	t.Logf("Base32   : %#v", id.Base32())
 	t.Logf("Base36   : %#v", id.Base36())
 	t.Logf("Base58   : %#v", id.Base58())
+	t.Logf("Base62   : %#v", id.Base62())
+	t.Logf("Crockford: %#v", id.Base32Crockford())
+	t.Logf("Hex      : %#v", id.Hex())
 	t.Logf("Base64   : %#v", id.Base64())
+	t.Logf("Base64URL: %#v", id.Base64URL())
 	t.Logf("Bytes    : %#v", id.Bytes())
 	t.Logf("IntBytes : %#v", id.IntBytes())
 
@@ -460,8 +497,13 @@ func TestUnmarshalJSON(t *testing.T) {
 		expectedErr error
 	}{
 		{`"13587"`, 13587, nil},
-		{`1`, 0, JSONSyntaxError{[]byte(`1`)}},
-		{`"invalid`, 0, JSONSyntaxError{[]byte(`"invalid`)}},
+		{`13587`, 13587, nil},
+		{`"-1"`, -1, nil},
+		{`""`, 0, JSONSyntaxError{[]byte(`""`), 1}},
+		{`null`, 0, JSONSyntaxError{[]byte(`null`), 0}},
+		{`1.5`, 0, JSONSyntaxError{[]byte(`1.5`), 1}},
+		{`"12a3"`, 0, JSONSyntaxError{[]byte(`"12a3"`), 3}},
+		{`"invalid`, 0, JSONSyntaxError{[]byte(`"invalid`), 8}},
 	}
 
 	for _, tc := range tt {
@@ -477,6 +519,126 @@ func TestUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestMarshalText(t *testing.T) {
+	id := ID(13587)
+	b, err := id.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "13587" {
+		t.Fatalf("Got %s, expected 13587", b)
+	}
+
+	var pID ID
+	if err := pID.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if pID != id {
+		t.Fatalf("pID %v != id %v", pID, id)
+	}
+	if err := pID.UnmarshalText([]byte("invalid")); err == nil {
+		t.Fatal("no error unmarshaling invalid text")
+	}
+
+	// map key
+	m := map[ID]string{id: "a"}
+	js, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(js) != `{"13587":"a"}` {
+		t.Fatalf("Got %s, expected {\"13587\":\"a\"}", js)
+	}
+	m2 := make(map[ID]string)
+	if err := json.Unmarshal(js, &m2); err != nil {
+		t.Fatal(err)
+	}
+	if m2[id] != "a" {
+		t.Fatalf("Got %v, expected %v", m2, m)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	id := ID(13587)
+	b, err := id.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x35, 0x13}
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("Expected ID to be encoded as %v, got %v", expected, b)
+	}
+
+	var pID ID
+	if err := pID.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if pID != id {
+		t.Fatalf("pID %v != id %v", pID, id)
+	}
+	if err := pID.UnmarshalBinary(b[1:]); err != ErrInvalidBinary {
+		t.Fatalf("Expected ErrInvalidBinary, got %v", err)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type record struct {
+		ID   ID
+		Name string
+	}
+	in := record{ID: MustNew(Node(1)).ID(), Name: "gob"}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Fatalf("Got %+v, expected %+v", out, in)
+	}
+}
+
+func TestIDNumberJSON(t *testing.T) {
+	type record struct {
+		ID  IDNumber `json:"id"`
+		Ref NullID   `json:"ref"`
+	}
+	in := record{ID: 13587}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"id":13587,"ref":null}` {
+		t.Fatalf("Got %s", b)
+	}
+
+	var out record
+	if err := json.Unmarshal([]byte(`{"id":"13587","ref":13588}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID.ID() != 13587 || !out.Ref.Valid || out.Ref.ID != 13588 {
+		t.Fatalf("Got %+v", out)
+	}
+	b, _ = json.Marshal(out)
+	if string(b) != `{"id":13587,"ref":"13588"}` {
+		t.Fatalf("Got %s", b)
+	}
+
+	if err := json.Unmarshal([]byte(`{"ref":null}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Ref.Valid {
+		t.Fatalf("Expected invalid NullID, got %+v", out.Ref)
+	}
+	err = json.Unmarshal([]byte(`{"id":true}`), &out)
+	if _, ok := err.(JSONSyntaxError); !ok {
+		t.Fatalf("Expected JSONSyntaxError, got %v", err)
+	}
+}
+
 // ****************************************************************************
 // Benchmark Methods
 
@@ -537,6 +699,17 @@ func BenchmarkGenerate(b *testing.B) {
 	}
 }
 
+func BenchmarkBatch(b *testing.B) {
+	sf, _ := New(Node(1))
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n += 1024 {
+		_ = sf.Batch(1024)
+	}
+}
+
 func BenchmarkGenerateMaxSequence(b *testing.B) {
 	sf, _ := New(NodeBits(1), SeqBits(21))
 
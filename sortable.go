@@ -0,0 +1,94 @@
+package snowflake
+
+//********************************************************************************
+// Sortable 定长编码, 使用 ASCII 顺序的编码表并在左侧补零,
+// 字符串顺序与 ID 数值顺序一致 (负数 ID 按无符号数排在所有非负 ID 之后),
+// 适用于键值存储的键及对象存储前缀
+
+const (
+	SortableBase32Len = 13 // SortableBase32 编码长度
+	SortableBase62Len = 11 // SortableBase62 编码长度
+	PaddedStringLen   = 20 // PaddedString 编码长度
+)
+
+// SortableBase32 返回定长 Crockford Base32 编码 ID
+func (f ID) SortableBase32() string {
+	var b [SortableBase32Len]byte
+	return string(f.AppendSortableBase32(b[:0]))
+}
+
+// AppendSortableBase32 追加定长 Crockford Base32 编码 ID
+func (f ID) AppendSortableBase32(dst []byte) []byte {
+	return appendFixed(dst, uint64(f), encodeCrockfordMap, SortableBase32Len)
+}
+
+// ParseSortableBase32 转化定长 Crockford Base32 编码字符串到 ID 类型
+func ParseSortableBase32(s string) (ID, error) {
+	return parseFixed(s, &decodeCrockfordMap, 32, SortableBase32Len, ErrInvalidCrockford)
+}
+
+// SortableBase62 返回定长 Base62 编码 ID
+func (f ID) SortableBase62() string {
+	var b [SortableBase62Len]byte
+	return string(f.AppendSortableBase62(b[:0]))
+}
+
+// AppendSortableBase62 追加定长 Base62 编码 ID
+func (f ID) AppendSortableBase62(dst []byte) []byte {
+	return appendFixed(dst, uint64(f), encodeBase62Map, SortableBase62Len)
+}
+
+// ParseSortableBase62 转化定长 Base62 编码字符串到 ID 类型
+func ParseSortableBase62(s string) (ID, error) {
+	return parseFixed(s, &decodeBase62Map, 62, SortableBase62Len, ErrInvalidBase62)
+}
+
+// PaddedString 返回定长十进制字符串 ID
+func (f ID) PaddedString() string {
+	var b [PaddedStringLen]byte
+	return string(f.AppendPaddedString(b[:0]))
+}
+
+// AppendPaddedString 追加定长十进制字符串 ID
+func (f ID) AppendPaddedString(dst []byte) []byte {
+	return appendFixed(dst, uint64(f), "0123456789", PaddedStringLen)
+}
+
+// ParsePaddedString 转化定长十进制字符串到 ID 类型
+func ParsePaddedString(s string) (ID, error) {
+	return parseFixed(s, &decodeDecimalMap, 10, PaddedStringLen, ErrInvalidDecimal)
+}
+
+// appendFixed 使用编码表 alphabet 追加 v 的定长编码, width 必须足够容纳 v
+func appendFixed(dst []byte, v uint64, alphabet string, width int) []byte {
+	base := uint64(len(alphabet))
+	n := len(dst)
+	dst = grow(dst, width)
+	for i := len(dst) - 1; i >= n; i-- {
+		dst[i] = alphabet[v%base]
+		v /= base
+	}
+	return dst
+}
+
+// parseFixed 使用解码表 table 转化长度为 width 的编码字符串, 按无符号数解码
+func parseFixed(s string, table *[256]byte, base uint64, width int, invalid error) (ID, error) {
+	if s == "" {
+		return -1, ErrEmpty
+	}
+	if len(s) != width {
+		return -1, ErrInvalidLength
+	}
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		d := table[s[i]]
+		if d == 0xFF {
+			return -1, invalid
+		}
+		if v > (1<<64-1-uint64(d))/base {
+			return -1, ErrOverflow
+		}
+		v = v*base + uint64(d)
+	}
+	return ID(v), nil
+}
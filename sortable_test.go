@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+var sortableCodecs = []struct {
+	name   string
+	length int
+	encode func(ID) string
+	parse  func(string) (ID, error)
+}{
+	{"SortableBase32", SortableBase32Len, ID.SortableBase32, ParseSortableBase32},
+	{"SortableBase62", SortableBase62Len, ID.SortableBase62, ParseSortableBase62},
+	{"PaddedString", PaddedStringLen, ID.PaddedString, ParsePaddedString},
+}
+
+func TestSortable(t *testing.T) {
+	ids := []ID{0, 1, 9, 10, 31, 32, 61, 62, 13587, 1116766490855473152, math.MaxInt64, -1, math.MinInt64}
+	for _, c := range sortableCodecs {
+		for _, id := range ids {
+			s := c.encode(id)
+			if len(s) != c.length {
+				t.Fatalf("%s(%d) = %q, expected length %d", c.name, id, s, c.length)
+			}
+			pID, err := c.parse(s)
+			if err != nil {
+				t.Fatalf("Parse%s(%q) error %s", c.name, s, err)
+			}
+			if pID != id {
+				t.Fatalf("Parse%s: pID %v != id %v", c.name, pID, id)
+			}
+		}
+		if _, err := c.parse("0"); err != ErrInvalidLength {
+			t.Fatalf("Parse%s: expected ErrInvalidLength, got %v", c.name, err)
+		}
+		if _, err := c.parse(strings.Repeat("!", c.length)); err == nil {
+			t.Fatalf("Parse%s: no error parsing invalid characters", c.name)
+		}
+		if _, err := c.parse(strings.Repeat("z", c.length)); c.name != "PaddedString" && err != ErrOverflow {
+			t.Fatalf("Parse%s: expected ErrOverflow, got %v", c.name, err)
+		}
+	}
+	if _, err := ParsePaddedString("99999999999999999999"); err != ErrOverflow {
+		t.Fatalf("ParsePaddedString: expected ErrOverflow, got %v", err)
+	}
+	if s := ID(13587).PaddedString(); s != "00000000000000013587" {
+		t.Fatalf("PaddedString(13587) = %q", s)
+	}
+}
+
+// 字符串顺序与无符号数值顺序一致
+func TestSortableOrder(t *testing.T) {
+	for _, c := range sortableCodecs {
+		c := c
+		prop := func(a, b int64) bool {
+			x, y := c.encode(ID(a)), c.encode(ID(b))
+			switch {
+			case uint64(a) < uint64(b):
+				return x < y
+			case uint64(a) > uint64(b):
+				return x > y
+			default:
+				return x == y
+			}
+		}
+		if err := quick.Check(prop, &quick.Config{MaxCount: 10000}); err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+	}
+}
+
+func TestSortableGenerated(t *testing.T) {
+	sf := MustNew(Node(1))
+	ids := sf.Batch(2000)
+	for _, c := range sortableCodecs {
+		ss := make([]string, len(ids))
+		for i := range ids {
+			ss[len(ids)-1-i] = c.encode(ids[i])
+		}
+		sort.Strings(ss)
+		for i, s := range ss {
+			if id, _ := c.parse(s); id != ids[i] {
+				t.Fatalf("%s: sorted position %d has id %d, expected %d", c.name, i, id, ids[i])
+			}
+		}
+	}
+}
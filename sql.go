@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+//********************************************************************************
+// database/sql
+
+// Scan 实现 sql.Scanner 接口, 支持 int64, []byte 及 string 类型数据源
+// NULL 值返回错误, 可空列请使用 NullID
+func (f *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*f = ID(v)
+		return nil
+	case []byte:
+		id, err := ParseBytes(v)
+		if err != nil {
+			return err
+		}
+		*f = id
+		return nil
+	case string:
+		id, err := ParseString(v)
+		if err != nil {
+			return err
+		}
+		*f = id
+		return nil
+	case nil:
+		return fmt.Errorf("snowflake: cannot scan NULL into ID")
+	default:
+		return fmt.Errorf("snowflake: cannot scan type %T into ID", src)
+	}
+}
+
+// Value 实现 driver.Valuer 接口, 以 64 位整型存储
+func (f ID) Value() (driver.Value, error) {
+	return int64(f), nil
+}
+
+// NullID 可为 NULL 的 ID, 与 sql.NullInt64 用法相同
+type NullID struct {
+	ID    ID
+	Valid bool // ID 不为 NULL 时为 true
+}
+
+// Scan 实现 sql.Scanner 接口
+func (n *NullID) Scan(src interface{}) error {
+	if src == nil {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	if err := n.ID.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value 实现 driver.Valuer 接口
+func (n NullID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.ID), nil
+}
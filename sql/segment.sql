@@ -0,0 +1,9 @@
+-- Segment
+CREATE TABLE public.snowflake_segment (
+    biz_tag     varchar(128) NOT NULL PRIMARY KEY,
+    max_id      bigint       NOT NULL DEFAULT 0,
+    description varchar(256)
+);
+ALTER TABLE public.snowflake_segment OWNER TO postgres;
+
+-- INSERT INTO public.snowflake_segment (biz_tag) VALUES ('order');
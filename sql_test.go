@@ -0,0 +1,176 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+//******************************************************************************
+// Fake driver, INSERT 保存参数, SELECT 依次返回保存的值
+
+type fakeDriver struct {
+	mu     sync.Mutex
+	values []driver.Value
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+type fakeRows struct {
+	values []driver.Value
+	i      int
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("snowflake-fake", fake)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	switch s.query {
+	case "INSERT":
+		s.c.d.values = append(s.c.d.values, args...)
+	case "DELETE":
+		s.c.d.values = nil
+	}
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	return &fakeRows{values: append([]driver.Value(nil), s.c.d.values...)}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	dest[0] = r.values[r.i]
+	r.i++
+	return nil
+}
+
+func openFakeDB(t *testing.T, values ...driver.Value) *sql.DB {
+	db, err := sql.Open("snowflake-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("DELETE"); err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	fake.values = values
+	fake.mu.Unlock()
+	return db
+}
+
+//******************************************************************************
+// database/sql Test funcs
+
+func TestValue(t *testing.T) {
+	db := openFakeDB(t)
+	defer db.Close()
+
+	if _, err := db.Exec("INSERT", ID(13587), NullID{ID: 42, Valid: true}, NullID{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []driver.Value{int64(13587), int64(42), nil}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.values) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(fake.values))
+	}
+	for i, v := range expected {
+		if fake.values[i] != v {
+			t.Fatalf("expected value %#v, got %#v", v, fake.values[i])
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	db := openFakeDB(t, int64(13587), []byte("13588"), "13589")
+	defer db.Close()
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	expected := ID(13587)
+	for rows.Next() {
+		var id ID
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		if id != expected {
+			t.Fatalf("expected id %d, got %d", expected, id)
+		}
+		expected++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if expected != 13590 {
+		t.Fatalf("scanned %d rows, expected 3", expected-13587)
+	}
+}
+
+func TestScanNull(t *testing.T) {
+	db := openFakeDB(t, nil, "13587")
+	defer db.Close()
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var n NullID
+	rows.Next()
+	if err := rows.Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Valid {
+		t.Fatalf("expected invalid NullID, got %+v", n)
+	}
+	rows.Next()
+	if err := rows.Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || n.ID != 13587 {
+		t.Fatalf("expected valid NullID 13587, got %+v", n)
+	}
+}
+
+func TestScanInvalid(t *testing.T) {
+	var id ID
+	for _, src := range []interface{}{nil, "abc", []byte("1x"), 1.5} {
+		if err := id.Scan(src); err == nil {
+			t.Fatalf("no error scanning %#v", src)
+		}
+	}
+}
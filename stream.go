@@ -0,0 +1,40 @@
+package snowflake
+
+import "context"
+
+// Stream 返回持续产生 ID 的通道, ctx 取消后停止产生并关闭通道
+// 后台协程每次批量产生最多 bufferSize 个 ID, 不必为每个 ID 加锁;
+// bufferSize 小于等于 0 时使用 DefaultBufferSize
+// 调用方停止读取时必须取消 ctx, 否则后台协程无法退出
+func (sf *Snowflake) Stream(ctx context.Context, bufferSize int) <-chan ID {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	// 单批不超过一毫秒序列容量, 避免长时间持有锁
+	batch := bufferSize
+	if max := sf.MaxSeq() + 1; int64(batch) > max {
+		batch = int(max)
+	}
+
+	ch := make(chan ID, bufferSize)
+	go func() {
+		defer close(ch)
+		ids := make([]ID, batch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			sf.fill(ids)
+			for _, id := range ids {
+				select {
+				case ch <- id:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
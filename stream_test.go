@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	sf := MustNew(Node(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := sf.Stream(ctx, 4096)
+
+	var last ID
+	for i := 0; i < 100000; i++ {
+		id := <-ch
+		if id <= last {
+			t.Fatalf("id %d is not greater than %d", id, last)
+		}
+		last = id
+	}
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("stream not closed after cancel")
+		}
+	}
+}
+
+func TestStreamCancelled(t *testing.T) {
+	sf := MustNew(Node(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ch := sf.Stream(ctx, 0)
+	if cap(ch) != DefaultBufferSize {
+		t.Fatalf("expected buffer size %d, got %d", DefaultBufferSize, cap(ch))
+	}
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed after cancel")
+	}
+}
+
+func BenchmarkStream(b *testing.B) {
+	sf, _ := New(Node(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := sf.Stream(ctx, 4096)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		<-ch
+	}
+}
@@ -0,0 +1,127 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// PrefixSeparator 类型化 ID 前缀与编码之间的分隔符
+const PrefixSeparator = "_"
+
+// Prefix 类型化 ID 前缀, 通常由空结构体实现:
+//
+//	type User struct{}
+//	func (User) Prefix() string { return "usr" }
+//	type UserID = snowflake.TypedID[User]
+type Prefix interface {
+	Prefix() string
+}
+
+// PrefixError 类型化 ID 前缀不匹配时返回
+type PrefixError struct {
+	Expected string // 期望前缀
+	Got      string // 实际前缀, 缺少前缀时为空
+}
+
+func (e *PrefixError) Error() string {
+	if e.Got == "" {
+		return fmt.Sprintf("snowflake: missing ID prefix %q", e.Expected)
+	}
+	return fmt.Sprintf("snowflake: ID prefix %q does not match %q", e.Got, e.Expected)
+}
+
+// TypedID 携带实体类型前缀的 ID, 字符串形式为 前缀_Base58 编码, 如 usr_2bK9...
+// 解析时校验前缀, 避免混用不同实体的 ID
+type TypedID[P Prefix] ID
+
+// ParseTyped 转化带前缀的字符串到 TypedID 类型, 前缀不匹配时返回 *PrefixError
+func ParseTyped[P Prefix](s string) (TypedID[P], error) {
+	var p P
+	prefix := p.Prefix()
+	i := strings.LastIndex(s, PrefixSeparator)
+	if i < 0 {
+		return -1, &PrefixError{Expected: prefix}
+	}
+	if s[:i] != prefix {
+		return -1, &PrefixError{Expected: prefix, Got: s[:i]}
+	}
+	id, err := ParseBase58String(s[i+len(PrefixSeparator):])
+	return TypedID[P](id), err
+}
+
+// ID 返回不带类型的 ID
+func (t TypedID[P]) ID() ID {
+	return ID(t)
+}
+
+// Prefix 返回类型前缀
+func (t TypedID[P]) Prefix() string {
+	var p P
+	return p.Prefix()
+}
+
+// String 返回带前缀的字符串
+func (t TypedID[P]) String() string {
+	return string(t.AppendString(nil))
+}
+
+// AppendString 追加带前缀的字符串
+func (t TypedID[P]) AppendString(dst []byte) []byte {
+	dst = append(dst, t.Prefix()...)
+	dst = append(dst, PrefixSeparator...)
+	return ID(t).AppendBase58(dst)
+}
+
+// MarshalJSON 编码为带前缀的 JSON 字符串
+func (t TypedID[P]) MarshalJSON() ([]byte, error) {
+	dst := append(make([]byte, 0, 24), '"')
+	dst = t.AppendString(dst)
+	return append(dst, '"'), nil
+}
+
+// UnmarshalJSON 转化带前缀的 JSON 字符串到 TypedID 类型
+func (t *TypedID[P]) UnmarshalJSON(b []byte) error {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return JSONSyntaxError{original: b}
+	}
+	return t.UnmarshalText(b[1 : len(b)-1])
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+func (t TypedID[P]) MarshalText() ([]byte, error) {
+	return t.AppendString(nil), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+func (t *TypedID[P]) UnmarshalText(b []byte) error {
+	id, err := ParseTyped[P](string(b))
+	if err != nil {
+		return err
+	}
+	*t = id
+	return nil
+}
+
+// Scan 实现 sql.Scanner 接口, 字符串需带有匹配的前缀, 整型直接作为 ID
+func (t *TypedID[P]) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	default:
+		var id ID
+		if err := id.Scan(src); err != nil {
+			return err
+		}
+		*t = TypedID[P](id)
+		return nil
+	}
+}
+
+// Value 实现 driver.Valuer 接口, 存储带前缀的字符串
+// 存储到整型列时请使用 ID() 返回的 ID
+func (t TypedID[P]) Value() (driver.Value, error) {
+	return t.String(), nil
+}
@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type userPrefix struct{}
+
+func (userPrefix) Prefix() string { return "usr" }
+
+type orderPrefix struct{}
+
+func (orderPrefix) Prefix() string { return "ord" }
+
+type (
+	userID  = TypedID[userPrefix]
+	orderID = TypedID[orderPrefix]
+)
+
+func TestTypedID(t *testing.T) {
+	id := MustNew(Node(1)).ID()
+	uid := userID(id)
+	s := uid.String()
+	if s != "usr_"+id.Base58() {
+		t.Fatalf("String() = %q, expected %q", s, "usr_"+id.Base58())
+	}
+
+	pID, err := ParseTyped[userPrefix](s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pID != uid || pID.ID() != id {
+		t.Fatalf("pID %v != uid %v", pID, uid)
+	}
+
+	var pe *PrefixError
+	if _, err := ParseTyped[orderPrefix](s); !errors.As(err, &pe) || pe.Expected != "ord" || pe.Got != "usr" {
+		t.Fatalf("Expected PrefixError, got %v", err)
+	}
+	if _, err := ParseTyped[userPrefix](id.Base58()); !errors.As(err, &pe) || pe.Got != "" {
+		t.Fatalf("Expected PrefixError, got %v", err)
+	}
+	if _, err := ParseTyped[userPrefix]("usr_0"); err != ErrInvalidBase58 {
+		t.Fatalf("Expected ErrInvalidBase58, got %v", err)
+	}
+}
+
+func TestTypedIDJSON(t *testing.T) {
+	type order struct {
+		ID    orderID        `json:"id"`
+		Buyer userID         `json:"buyer"`
+		Items map[userID]int `json:"items"`
+	}
+	in := order{ID: 13587, Buyer: 13588, Items: map[userID]int{13589: 1}}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":"ord_53g","buyer":"usr_53h","items":{"usr_53i":1}}`
+	if string(b) != expected {
+		t.Fatalf("Got %s, expected %s", b, expected)
+	}
+
+	var out order
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != in.ID || out.Buyer != in.Buyer || out.Items[13589] != 1 {
+		t.Fatalf("Got %+v, expected %+v", out, in)
+	}
+
+	var pe *PrefixError
+	err = json.Unmarshal([]byte(`{"id":"usr_53g"}`), &out)
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected PrefixError, got %v", err)
+	}
+	err = json.Unmarshal([]byte(`{"id":13587}`), &out)
+	if _, ok := err.(JSONSyntaxError); !ok {
+		t.Fatalf("Expected JSONSyntaxError, got %v", err)
+	}
+}
+
+func TestTypedIDSQL(t *testing.T) {
+	uid := userID(13587)
+	v, err := uid.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "usr_53g" {
+		t.Fatalf("Value() = %v, expected usr_53g", v)
+	}
+
+	for _, src := range []interface{}{"usr_53g", []byte("usr_53g"), int64(13587)} {
+		var pID userID
+		if err := pID.Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		if pID != uid {
+			t.Fatalf("Scan(%#v) = %v, expected %v", src, pID, uid)
+		}
+	}
+	var oid orderID
+	var pe *PrefixError
+	if err := oid.Scan("usr_53g"); !errors.As(err, &pe) {
+		t.Fatalf("Expected PrefixError, got %v", err)
+	}
+}